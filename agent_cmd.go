@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"uhh/internal/agent"
+	"uhh/internal/output"
+	"uhh/internal/shell"
+	"uhh/internal/tools"
+	"uhh/internal/tui"
+
+	"github.com/spf13/cobra"
+)
+
+var agentAutoApproveFlag bool
+var agentProfileFlag string
+
+var agentCmd = &cobra.Command{
+	Use:   "agent [prompt]",
+	Short: "Run in agent mode, with tool calling to execute multi-step tasks",
+	Args:  cobra.ArbitraryArgs,
+	RunE:  runAgent,
+}
+
+func init() {
+	agentCmd.Flags().BoolVarP(&agentAutoApproveFlag, "auto-approve", "y", false, "Auto-approve tool executions")
+	agentCmd.Flags().StringVarP(&agentProfileFlag, "agent-profile", "A", "", "Named agent profile to use (see config.Agents), restricting its system prompt and available tools")
+}
+
+func runAgent(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cfg, err := loadEffectiveConfig()
+	if err != nil {
+		return err
+	}
+
+	userPrompt := readPromptArgs(args)
+	if userPrompt == "" {
+		output.PrintWarn("No prompt provided. Exiting.")
+		os.Exit(1)
+	}
+
+	p, _, err := resolveProvider(cfg)
+	if err != nil {
+		return err
+	}
+	if !p.SupportsToolCalling() {
+		return fmt.Errorf("provider %s does not support tool calling", p.Name())
+	}
+
+	shellName := resolveShell(cfg)
+
+	var profile agent.Profile
+	if agentProfileFlag != "" {
+		ap, ok := cfg.Agents[agentProfileFlag]
+		if !ok {
+			return fmt.Errorf("unknown agent profile: %s", agentProfileFlag)
+		}
+		profile = agent.Profile{
+			Name:         agentProfileFlag,
+			SystemPrompt: ap.SystemPrompt,
+			Tools:        ap.Tools,
+			Temperature:  ap.Temperature,
+		}
+	}
+
+	toolRegistry := tools.DefaultRegistryWithConfig(cfg.Shell)
+
+	// Register any external tool plugins found under tools.ToolsDir()
+	for _, err := range tools.LoadExternal(toolRegistry) {
+		output.PrintWarn(fmt.Sprintf("tool plugin: %v", err))
+	}
+
+	agentConfig := agent.Config{
+		AutoApprove:   agentAutoApproveFlag || cfg.Agent.AutoApprove,
+		MaxIterations: cfg.Agent.MaxIterations,
+		Temperature:   cfg.Providers[cfg.GetActiveProvider()].Temperature,
+		Pricing:       pricingTable(cfg),
+	}
+	if profile.Temperature != 0 {
+		agentConfig.Temperature = profile.Temperature
+	}
+
+	var a *agent.Agent
+	if profile.Name != "" {
+		a = agent.NewWithProfile(p, toolRegistry, agentConfig, profile)
+	} else {
+		a = agent.New(p, toolRegistry, agentConfig)
+	}
+
+	systemPrompt := shell.BuildAgentSystemPrompt(shellName)
+	if profile.SystemPrompt != "" {
+		systemPrompt = profile.SystemPrompt
+	}
+	a.SetSystemPrompt(systemPrompt)
+	if !agentConfig.AutoApprove {
+		a.SetConfirmFunc(tui.ConfirmToolExecution)
+		a.SetFileWriteConfirmFunc(tui.ConfirmFileWrite)
+		if tui.InteractiveEnabled(interactiveFlag) {
+			a.SetDangerousConfirmFunc(tui.ConfirmDangerousInteractive)
+		}
+	}
+
+	if workingDir, err := os.Getwd(); err == nil {
+		if err := agent.NewContextAttacher(cfg.Shell).Attach(a.Context(), userPrompt, workingDir); err != nil {
+			output.PrintWarn(fmt.Sprintf("context attach: %v", err))
+		}
+	}
+
+	events, err := a.RunStream(ctx, userPrompt)
+	if err != nil {
+		return err
+	}
+
+	var result *agent.Result
+	for event := range events {
+		switch event.Type {
+		case agent.EventAssistantDelta:
+			fmt.Print(output.CommandString(event.Text))
+		case agent.EventToolCallStart:
+			output.PrintTool(event.ToolName)
+			fmt.Println(event.ToolInput)
+		case agent.EventToolCallResult:
+			if event.Err != nil {
+				output.PrintToolError(event.ToolName, event.Err)
+			}
+		case agent.EventFinal:
+			result = event.Result
+		}
+	}
+	fmt.Println()
+
+	if result == nil {
+		return ctx.Err()
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if len(result.ToolsUsed) > 0 || result.Usage.TotalTokens > 0 {
+		output.PrintDim(fmt.Sprintf("Used %d tools in %d iterations%s", len(result.ToolsUsed), result.Iterations, formatUsage(result.Usage)))
+	}
+
+	return nil
+}
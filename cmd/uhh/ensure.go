@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"uhh/internal/config"
+	"uhh/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var ensureFile string
+
+var ensureCmd = &cobra.Command{
+	Use:   "ensure",
+	Short: "Reconcile the on-disk config to match a declarative manifest",
+	Long:  "Reads a declarative uhh.yaml manifest and reconciles the on-disk config to match it, reporting a diff before applying. Useful for provisioning machines non-interactively.",
+	Run:   runEnsure,
+}
+
+func init() {
+	ensureCmd.Flags().StringVarP(&ensureFile, "file", "f", "uhh.yaml", "Path to the manifest file")
+	rootCmd.AddCommand(ensureCmd)
+}
+
+func runEnsure(cmd *cobra.Command, args []string) {
+	manifest, err := config.LoadManifest(ensureFile)
+	if err != nil {
+		output.PrintError(fmt.Sprintf("Failed to load manifest: %v", err))
+		os.Exit(1)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		output.PrintError(fmt.Sprintf("Failed to load config: %v", err))
+		os.Exit(1)
+	}
+
+	changes := manifest.Diff(cfg)
+	if len(changes) == 0 {
+		output.PrintSuccess("Config already matches manifest. Nothing to do.")
+		return
+	}
+
+	output.PrintInfo(fmt.Sprintf("Applying %s would make the following changes:", ensureFile))
+	for _, change := range changes {
+		fmt.Printf("  ~ %s\n", change)
+	}
+
+	manifest.Apply(cfg)
+
+	if err := cfg.Save(); err != nil {
+		output.PrintError(fmt.Sprintf("Failed to save config: %v", err))
+		os.Exit(1)
+	}
+
+	output.PrintSuccess(fmt.Sprintf("Config reconciled with %s (%d changes applied).", ensureFile, len(changes)))
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"uhh/internal/config"
+	"uhh/internal/gallery"
+	"uhh/internal/output"
+	"uhh/internal/tui"
+
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+var galleryCmd = &cobra.Command{
+	Use:   "gallery",
+	Short: "Browse and install recommended models from the model gallery",
+}
+
+var galleryListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List models available in the gallery",
+	Run:   runGalleryList,
+}
+
+var galleryInstallCmd = &cobra.Command{
+	Use:   "install <id>",
+	Short: "Install a gallery model as a profile, prompting for an API key if needed",
+	Args:  cobra.ExactArgs(1),
+	Run:   runGalleryInstall,
+}
+
+var gallerySetDefault bool
+
+func init() {
+	galleryInstallCmd.Flags().BoolVar(&gallerySetDefault, "default", false, "Also make this the default provider and profile")
+
+	galleryCmd.AddCommand(galleryListCmd)
+	galleryCmd.AddCommand(galleryInstallCmd)
+	rootCmd.AddCommand(galleryCmd)
+}
+
+func runGalleryList(cmd *cobra.Command, args []string) {
+	cfg := loadConfigOrExit()
+
+	entries, err := gallery.List(context.Background(), cfg)
+	if err != nil {
+		output.PrintError(fmt.Sprintf("Failed to fetch gallery index: %v", err))
+		os.Exit(1)
+	}
+
+	if len(entries) == 0 {
+		output.PrintInfo("Gallery index is empty.")
+		return
+	}
+
+	for _, e := range entries {
+		fmt.Printf("%s  (%s)\n", e.ID, config.ProviderDisplayNames[e.Provider])
+		if len(e.RecommendedFor) > 0 {
+			fmt.Printf("  recommended for: %v\n", e.RecommendedFor)
+		}
+		if e.ContextWindow > 0 {
+			fmt.Printf("  context window: %d\n", e.ContextWindow)
+		}
+	}
+}
+
+func runGalleryInstall(cmd *cobra.Command, args []string) {
+	cfg := loadConfigOrExit()
+	id := args[0]
+
+	entry, err := gallery.Get(context.Background(), cfg, id)
+	if err != nil {
+		output.PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	apiKey := cfg.Providers[entry.Provider].APIKey
+	if apiKey == "" {
+		form := huh.NewForm(
+			huh.NewGroup(
+				huh.NewInput().
+					Title(fmt.Sprintf("Enter your %s API key", config.ProviderDisplayNames[entry.Provider])).
+					Description("Leave blank to configure it later.").
+					Placeholder("sk-...").
+					EchoMode(huh.EchoModePassword).
+					Value(&apiKey),
+			),
+		).WithTheme(tui.GetTheme())
+		if err := form.Run(); err != nil {
+			output.PrintError(err.Error())
+			os.Exit(1)
+		}
+	}
+
+	gallery.Install(cfg, *entry, apiKey, gallerySetDefault)
+	saveConfigOrExit(cfg)
+
+	output.PrintSuccess(fmt.Sprintf("Installed %s as profile %q (use --profile %s or -P %s to select it)", entry.ID, entry.ID, entry.ID, entry.ID))
+}
@@ -5,7 +5,11 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
 	"strings"
+	"time"
 
 	"uhh/internal/agent"
 	"uhh/internal/config"
@@ -14,6 +18,7 @@ import (
 	"uhh/internal/provider"
 	"uhh/internal/shell"
 	"uhh/internal/tools"
+	"uhh/internal/tools/mcp"
 	"uhh/internal/tui"
 	"uhh/internal/updater"
 
@@ -32,6 +37,12 @@ var (
 	modelFlag      string
 	autoApproveFlag bool
 	agentModeFlag  bool
+	agentProfileFlag string
+	profileFlag    string
+	interactiveFlag bool
+	candidatesFlag  int
+	verifyOnlyFlag  bool
+	channelFlag     string
 
 	// Root command
 	rootCmd = &cobra.Command{
@@ -83,6 +94,14 @@ and can execute commands with tool calling.`,
 		Run:   runUpdate,
 	}
 
+	// Update rollback subcommand
+	updateRollbackCmd = &cobra.Command{
+		Use:   "rollback [version]",
+		Short: "Roll back to a previously installed binary (latest backup if version is omitted)",
+		Args:  cobra.MaximumNArgs(1),
+		Run:   runUpdateRollback,
+	}
+
 	// Version command
 	versionCmd = &cobra.Command{
 		Use:   "version",
@@ -103,6 +122,13 @@ func init() {
 	rootCmd.Flags().StringVarP(&modelFlag, "model", "m", "", "Model to use")
 	rootCmd.Flags().BoolVarP(&autoApproveFlag, "auto-approve", "y", false, "Auto-approve tool executions")
 	rootCmd.Flags().BoolVarP(&agentModeFlag, "agent", "a", false, "Run in agent mode with tool calling")
+	rootCmd.Flags().StringVarP(&agentProfileFlag, "agent-profile", "A", "", "Named agent profile to use in agent mode (see config.Agents), restricting its system prompt and available tools")
+	rootCmd.PersistentFlags().StringVarP(&profileFlag, "profile", "P", "", "Configuration profile to apply (overrides UHH_PROFILE and default_profile)")
+	rootCmd.PersistentFlags().BoolVarP(&interactiveFlag, "interactive", "i", false, "Review candidates and confirm before running/copying (also UHH_INTERACTIVE=1)")
+	rootCmd.PersistentFlags().IntVar(&candidatesFlag, "candidates", 3, "Number of candidates to generate in interactive mode")
+	updateCmd.Flags().BoolVar(&verifyOnlyFlag, "verify-only", false, "Run the download/checksum/signature verification pipeline without installing")
+	updateCmd.Flags().StringVar(&channelFlag, "channel", "", "Release channel to check (stable, beta, nightly); overrides the configured default")
+	updateCmd.AddCommand(updateRollbackCmd)
 
 	// Add subcommands
 	rootCmd.AddCommand(initCmd)
@@ -116,6 +142,11 @@ func init() {
 }
 
 func main() {
+	for _, err := range provider.Load() {
+		output.PrintWarn(fmt.Sprintf("plugin: %v", err))
+	}
+	defer provider.Shutdown()
+
 	if err := rootCmd.Execute(); err != nil {
 		output.PrintError(err.Error())
 		os.Exit(1)
@@ -123,7 +154,8 @@ func main() {
 }
 
 func runMain(cmd *cobra.Command, args []string) {
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
 	// Load config
 	cfg, err := config.Load()
@@ -132,6 +164,20 @@ func runMain(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	// Apply the selected profile (flag takes priority over UHH_PROFILE/default_profile)
+	profileName := profileFlag
+	if profileName == "" {
+		profileName = cfg.GetActiveProfile()
+	}
+	if profileName != "" {
+		overlaid, err := cfg.WithProfile(profileName)
+		if err != nil {
+			output.PrintError(fmt.Sprintf("Failed to apply profile: %v", err))
+			os.Exit(1)
+		}
+		cfg = overlaid
+	}
+
 	// Apply flag overrides
 	if cfg.UI.NoColor {
 		output.DisableColors()
@@ -177,6 +223,16 @@ func runMain(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	// A provider declared directly in config via Command (rather than
+	// discovered under provider.PluginsDir()) is spawned and registered here,
+	// on first use.
+	if providerSettings.Command != "" {
+		if err := provider.RegisterCommand(providerName, providerSettings.Command, providerSettings.Args, providerSettings.Env, providerSettings.Socket); err != nil {
+			output.PrintError(fmt.Sprintf("Failed to start provider plugin %s: %v", providerName, err))
+			os.Exit(1)
+		}
+	}
+
 	// Initialize provider
 	p, err := provider.GetAndInitialize(providerName, provider.Config{
 		APIKey:      providerSettings.APIKey,
@@ -208,15 +264,17 @@ func runMain(cmd *cobra.Command, args []string) {
 	}
 
 	// Determine if we should use agent mode
-	useAgent := agentModeFlag || (cfg.Agent.EnabledTools != nil && len(cfg.Agent.EnabledTools) > 0 && p.SupportsToolCalling())
+	useAgent := agentModeFlag || agentProfileFlag != "" || (cfg.Agent.EnabledTools != nil && len(cfg.Agent.EnabledTools) > 0 && p.SupportsToolCalling())
 
 	var completion string
+	var usage agent.Usage
 
 	if useAgent && p.SupportsToolCalling() {
 		// Agent mode with tool calling
-		completion, err = runAgentMode(ctx, p, cfg, userPrompt, shellName)
+		completion, usage, err = runAgentMode(ctx, p, cfg, userPrompt, shellName)
 	} else {
-		// Simple mode
+		// Simple mode - Stream doesn't expose GenerationInfo, so this path's
+		// history entries carry zero usage.
 		completion, err = runSimpleMode(ctx, p, cfg, userPrompt, shellName)
 	}
 
@@ -225,59 +283,250 @@ func runMain(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Output result
-	output.PrintCommand(completion)
-
 	// Copy to clipboard
 	if err := clipboard.WriteAll(completion); err == nil {
 		output.PrintSuccess("Copied to clipboard!")
 	}
 
 	// Log history
-	history.Log(shellName, userPrompt, completion)
+	history.LogEntry(history.Entry{
+		Time:   time.Now(),
+		Shell:  shellName,
+		Prompt: userPrompt,
+		Output: completion,
+		Usage: history.Usage{
+			PromptTokens:     usage.PromptTokens,
+			CompletionTokens: usage.CompletionTokens,
+			TotalTokens:      usage.TotalTokens,
+			CostUSD:          usage.CostUSD,
+		},
+	})
 }
 
 func runSimpleMode(ctx context.Context, p provider.Provider, cfg *config.Config, userPrompt, shellName string) (string, error) {
 	// Build prompt
 	prompt := shell.BuildPrompt(userPrompt, shellName, cfg.Shell.AppendFileContext, cfg.Shell.MaxContextTokens)
 
-	// Call LLM
-	return p.Call(ctx, prompt, llms.WithTemperature(cfg.Providers[cfg.DefaultProvider].Temperature))
+	if tui.InteractiveEnabled(interactiveFlag) {
+		return runInteractiveSimpleMode(ctx, p, cfg, prompt)
+	}
+
+	// Stream the completion, rendering it live as it arrives. Providers that
+	// don't support incremental streaming just emit one chunk with the full
+	// text, so this always produces correct output.
+	opts := []llms.CallOption{llms.WithTemperature(cfg.Providers[cfg.DefaultProvider].Temperature)}
+	if len(cfg.StopWords) > 0 {
+		opts = append(opts, llms.WithStopWords(cfg.StopWords))
+	}
+
+	chunks, err := p.Stream(ctx, prompt, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for chunk := range chunks {
+		if chunk.Err != nil {
+			return "", chunk.Err
+		}
+		fmt.Print(output.CommandString(chunk.Text))
+		sb.WriteString(chunk.Text)
+	}
+	fmt.Println()
+
+	return sb.String(), nil
 }
 
-func runAgentMode(ctx context.Context, p provider.Provider, cfg *config.Config, userPrompt, shellName string) (string, error) {
+// runInteractiveSimpleMode requests a batch of candidates and lets the user
+// review, edit, or regenerate them via tui.SelectCandidateAction before
+// anything is printed, copied, or run.
+func runInteractiveSimpleMode(ctx context.Context, p provider.Provider, cfg *config.Config, prompt string) (string, error) {
+	temperature := cfg.Providers[cfg.DefaultProvider].Temperature
+
+	regenerate := func() ([]string, error) {
+		return tui.GenerateCandidates(ctx, p, prompt, temperature, candidatesFlag)
+	}
+
+	candidates, err := regenerate()
+	if err != nil {
+		return "", err
+	}
+
+	action, command, err := tui.SelectCandidateAction(candidates, regenerate)
+	if err != nil {
+		return "", err
+	}
+
+	switch action {
+	case tui.ActionCancel:
+		output.PrintInfo("Cancelled.")
+		return "", nil
+	case tui.ActionCopy:
+		output.PrintCommand(command)
+	case tui.ActionRun:
+		output.PrintCommand(command)
+		if err := runShellCommand(ctx, command); err != nil {
+			return "", err
+		}
+	}
+
+	return command, nil
+}
+
+// runShellCommand executes command via the platform shell, the same way
+// tools.BashTool does, streaming output straight to the terminal.
+func runShellCommand(ctx context.Context, command string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func runAgentMode(ctx context.Context, p provider.Provider, cfg *config.Config, userPrompt, shellName string) (string, agent.Usage, error) {
+	// Resolve the named agent profile, if one was requested via
+	// --agent-profile. This may swap p for the profile's own provider.
+	var profile agent.Profile
+	if agentProfileFlag != "" {
+		ap, ok := cfg.Agents[agentProfileFlag]
+		if !ok {
+			return "", agent.Usage{}, fmt.Errorf("unknown agent profile: %s", agentProfileFlag)
+		}
+		profile = agent.Profile{
+			Name:         agentProfileFlag,
+			SystemPrompt: ap.SystemPrompt,
+			Tools:        ap.Tools,
+			Provider:     ap.Provider,
+			Model:        ap.Model,
+			Temperature:  ap.Temperature,
+		}
+
+		if profile.Provider != "" && profile.Provider != p.Name() {
+			providerSettings, ok := cfg.GetProviderSettings(profile.Provider)
+			if !ok {
+				return "", agent.Usage{}, fmt.Errorf("agent profile %s: unknown provider: %s", profile.Name, profile.Provider)
+			}
+			reinit, err := provider.GetAndInitialize(profile.Provider, provider.Config{
+				APIKey:      providerSettings.APIKey,
+				Model:       getModelOrDefault(profile.Model, profile.Provider),
+				BaseURL:     providerSettings.BaseURL,
+				Temperature: providerSettings.Temperature,
+			})
+			if err != nil {
+				return "", agent.Usage{}, fmt.Errorf("agent profile %s: failed to initialize provider %s: %w", profile.Name, profile.Provider, err)
+			}
+			p = reinit
+		}
+	}
+
 	// Create tool registry
-	toolRegistry := tools.DefaultRegistry()
+	toolRegistry := tools.DefaultRegistryWithConfig(cfg.Shell)
+
+	// Register any configured MCP servers' tools alongside the built-ins
+	for _, err := range mcp.RegisterAll(ctx, toolRegistry, cfg.MCPServers) {
+		output.PrintWarn(fmt.Sprintf("MCP: %v", err))
+	}
+
+	// Register any external tool plugins found under tools.ToolsDir()
+	for _, err := range tools.LoadExternal(toolRegistry) {
+		output.PrintWarn(fmt.Sprintf("tool plugin: %v", err))
+	}
 
 	// Create agent
 	agentConfig := agent.Config{
 		AutoApprove:   autoApproveFlag || cfg.Agent.AutoApprove,
 		MaxIterations: cfg.Agent.MaxIterations,
 		Temperature:   cfg.Providers[cfg.DefaultProvider].Temperature,
+		StopWords:     cfg.StopWords,
+		Pricing:       pricingTable(cfg),
+	}
+	if profile.Temperature != 0 {
+		agentConfig.Temperature = profile.Temperature
 	}
 
-	a := agent.New(p, toolRegistry, agentConfig)
+	var a *agent.Agent
+	if profile.Name != "" {
+		a = agent.NewWithProfile(p, toolRegistry, agentConfig, profile)
+	} else {
+		a = agent.New(p, toolRegistry, agentConfig)
+	}
 
-	// Set system prompt
-	a.SetSystemPrompt(shell.BuildAgentSystemPrompt(shellName))
+	// Set system prompt: an agent profile's own prompt wins; otherwise fall
+	// back to the active config profile's template, or the shell default.
+	systemPrompt := shell.BuildAgentSystemPrompt(shellName)
+	if cfg.SystemPromptTemplate != "" {
+		rendered, err := shell.RenderSystemPromptTemplate(cfg.SystemPromptTemplate, userPrompt, shellName, cfg.Shell.AppendFileContext, cfg.Shell.MaxContextTokens)
+		if err != nil {
+			output.PrintWarn(fmt.Sprintf("profile system prompt template: %v", err))
+		} else {
+			systemPrompt = rendered
+		}
+	}
+	if profile.SystemPrompt != "" {
+		systemPrompt = profile.SystemPrompt
+	}
+	a.SetSystemPrompt(systemPrompt)
 
 	// Set confirmation function if not auto-approve
 	if !agentConfig.AutoApprove {
 		a.SetConfirmFunc(tui.ConfirmToolExecution)
+		a.SetFileWriteConfirmFunc(tui.ConfirmFileWrite)
+		if tui.InteractiveEnabled(interactiveFlag) {
+			a.SetDangerousConfirmFunc(tui.ConfirmDangerousInteractive)
+		}
+	}
+
+	// Attach any piped stdin and @-file references ahead of the real prompt.
+	if workingDir, err := os.Getwd(); err == nil {
+		if err := agent.NewContextAttacher(cfg.Shell).Attach(a.Context(), userPrompt, workingDir); err != nil {
+			output.PrintWarn(fmt.Sprintf("context attach: %v", err))
+		}
 	}
 
-	// Run agent
-	result, err := a.Run(ctx, userPrompt)
+	// Run agent, rendering text deltas and tool activity live. A SIGINT
+	// (propagated via ctx from runMain) cancels cleanly: the goroutine
+	// observes ctx.Done() and emits a final event instead of hanging.
+	events, err := a.RunStream(ctx, userPrompt)
 	if err != nil {
-		return "", err
+		return "", agent.Usage{}, err
+	}
+
+	var result *agent.Result
+	for event := range events {
+		switch event.Type {
+		case agent.EventAssistantDelta:
+			fmt.Print(output.CommandString(event.Text))
+		case agent.EventToolCallStart:
+			output.PrintTool(event.ToolName)
+			fmt.Println(event.ToolInput)
+		case agent.EventToolCallResult:
+			if event.Err != nil {
+				output.PrintToolError(event.ToolName, event.Err)
+			}
+		case agent.EventFinal:
+			result = event.Result
+		}
+	}
+	fmt.Println()
+
+	if result == nil {
+		return "", agent.Usage{}, ctx.Err()
+	}
+	if result.Error != nil {
+		return "", agent.Usage{}, result.Error
 	}
 
 	// Print tool usage summary
-	if len(result.ToolsUsed) > 0 {
-		output.PrintDim(fmt.Sprintf("Used %d tools in %d iterations", len(result.ToolsUsed), result.Iterations))
+	if len(result.ToolsUsed) > 0 || result.Usage.TotalTokens > 0 {
+		output.PrintDim(fmt.Sprintf("Used %d tools in %d iterations%s", len(result.ToolsUsed), result.Iterations, formatUsage(result.Usage)))
 	}
 
-	return result.FinalAnswer, nil
+	return result.FinalAnswer, result.Usage, nil
 }
 
 func runInit(cmd *cobra.Command, args []string) {
@@ -343,6 +592,31 @@ func getModelOrDefault(model, providerName string) string {
 	return "gpt-4o"
 }
 
+// pricingTable translates cfg's plain config.ModelPricing map into a
+// provider.PricingTable, keeping the provider package free of any
+// dependency on config (mirroring how provider.Config and
+// config.ProviderSettings stay distinct types bridged here).
+func pricingTable(cfg *config.Config) provider.PricingTable {
+	table := make(provider.PricingTable, len(cfg.Pricing))
+	for model, p := range cfg.Pricing {
+		table[model] = provider.ModelPricing{InputPer1K: p.InputPer1K, OutputPer1K: p.OutputPer1K}
+	}
+	return table
+}
+
+// formatUsage renders a result's token/cost accounting for the
+// "Used N tools..." summary line, omitting the cost clause when no pricing
+// was configured for the model that produced it.
+func formatUsage(u agent.Usage) string {
+	if u.TotalTokens == 0 {
+		return ""
+	}
+	if u.CostUSD > 0 {
+		return fmt.Sprintf(", %d tokens ($%.4f)", u.TotalTokens, u.CostUSD)
+	}
+	return fmt.Sprintf(", %d tokens", u.TotalTokens)
+}
+
 func runConfigModels(cmd *cobra.Command, args []string) {
 	cfg, err := config.Load()
 	if err != nil {
@@ -465,9 +739,24 @@ func runConfigSet(cmd *cobra.Command, args []string) {
 
 func runUpdate(cmd *cobra.Command, args []string) {
 	output.PrintInfo(fmt.Sprintf("Current version: %s", version))
-	output.PrintInfo("Checking for updates...")
 
-	info, err := updater.CheckForUpdate(version)
+	cfg, err := config.Load()
+	if err != nil {
+		output.PrintError(fmt.Sprintf("Failed to load config: %v", err))
+		os.Exit(1)
+	}
+
+	channel := channelFlag
+	if channel == "" {
+		channel = cfg.Update.Channel
+	}
+	if channel == "" {
+		channel = updater.ChannelStable
+	}
+
+	output.PrintInfo(fmt.Sprintf("Checking for updates on the %s channel...", channel))
+
+	info, err := updater.CheckForUpdate(version, channel)
 	if err != nil {
 		output.PrintError(fmt.Sprintf("Failed to check for updates: %v", err))
 		os.Exit(1)
@@ -480,23 +769,69 @@ func runUpdate(cmd *cobra.Command, args []string) {
 
 	output.PrintInfo(fmt.Sprintf("New version available: %s", info.LatestVersion))
 
-	// Ask for confirmation
-	fmt.Print("Do you want to update? [y/N]: ")
-	var response string
-	fmt.Scanln(&response)
-	response = strings.ToLower(strings.TrimSpace(response))
+	opts := updater.UpdateOptions{
+		RequireSignature: cfg.Update.RequireSignature,
+		VerifyOnly:       verifyOnlyFlag,
+		MaxBackups:       cfg.Update.MaxBackups,
+	}
 
-	if response != "y" && response != "yes" {
-		output.PrintInfo("Update cancelled.")
-		return
+	if !verifyOnlyFlag {
+		// Ask for confirmation
+		fmt.Print("Do you want to update? [y/N]: ")
+		var response string
+		fmt.Scanln(&response)
+		response = strings.ToLower(strings.TrimSpace(response))
+
+		if response != "y" && response != "yes" {
+			output.PrintInfo("Update cancelled.")
+			return
+		}
 	}
 
-	output.PrintInfo("Downloading update...")
-	if err := updater.PerformUpdate(info); err != nil {
+	output.PrintInfo("Downloading and verifying update...")
+	if err := updater.PerformUpdate(info, opts); err != nil {
 		output.PrintError(fmt.Sprintf("Failed to update: %v", err))
 		os.Exit(1)
 	}
 
+	if info.Verified {
+		output.PrintSuccess("Release signature verified.")
+	}
+
+	if verifyOnlyFlag {
+		output.PrintSuccess(fmt.Sprintf("%s verified successfully (not installed).", info.LatestVersion))
+		return
+	}
+
 	output.PrintSuccess(fmt.Sprintf("Successfully updated to %s!", info.LatestVersion))
 	output.PrintInfo("Please restart uhh to use the new version.")
 }
+
+func runUpdateRollback(cmd *cobra.Command, args []string) {
+	var target string
+	if len(args) > 0 {
+		target = args[0]
+	}
+
+	if target == "" {
+		backups, err := updater.ListBackups()
+		if err != nil {
+			output.PrintError(fmt.Sprintf("Failed to list backups: %v", err))
+			os.Exit(1)
+		}
+		if len(backups) == 0 {
+			output.PrintError("No backups available to roll back to.")
+			os.Exit(1)
+		}
+		output.PrintInfo(fmt.Sprintf("Rolling back to the most recent backup: %s", backups[0].Version))
+	} else {
+		output.PrintInfo(fmt.Sprintf("Rolling back to %s...", target))
+	}
+
+	if err := updater.RollbackTo(target); err != nil {
+		output.PrintError(fmt.Sprintf("Rollback failed: %v", err))
+		os.Exit(1)
+	}
+
+	output.PrintSuccess("Rolled back successfully. Please restart uhh to use the restored version.")
+}
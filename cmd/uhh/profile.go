@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"uhh/internal/config"
+	"uhh/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var configProfileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage named configuration profiles",
+}
+
+var configProfileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured profiles",
+	Run:   runConfigProfileList,
+}
+
+var configProfileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Set the default profile",
+	Args:  cobra.ExactArgs(1),
+	Run:   runConfigProfileUse,
+}
+
+var configProfileCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create an empty profile (or from the active provider via --from-current)",
+	Args:  cobra.ExactArgs(1),
+	Run:   runConfigProfileCreate,
+}
+
+var configProfileDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a profile",
+	Args:  cobra.ExactArgs(1),
+	Run:   runConfigProfileDelete,
+}
+
+var configProfileCopyCmd = &cobra.Command{
+	Use:   "copy <src> <dst>",
+	Short: "Copy a profile under a new name",
+	Args:  cobra.ExactArgs(2),
+	Run:   runConfigProfileCopy,
+}
+
+var profileFromCurrent bool
+
+func init() {
+	configProfileCreateCmd.Flags().BoolVar(&profileFromCurrent, "from-current", false, "Seed the new profile with the current default provider and its settings")
+
+	configProfileCmd.AddCommand(configProfileListCmd)
+	configProfileCmd.AddCommand(configProfileUseCmd)
+	configProfileCmd.AddCommand(configProfileCreateCmd)
+	configProfileCmd.AddCommand(configProfileDeleteCmd)
+	configProfileCmd.AddCommand(configProfileCopyCmd)
+	configCmd.AddCommand(configProfileCmd)
+}
+
+func loadConfigOrExit() *config.Config {
+	cfg, err := config.Load()
+	if err != nil {
+		output.PrintError(fmt.Sprintf("Failed to load config: %v", err))
+		os.Exit(1)
+	}
+	return cfg
+}
+
+func saveConfigOrExit(cfg *config.Config) {
+	if err := cfg.Save(); err != nil {
+		output.PrintError(fmt.Sprintf("Failed to save config: %v", err))
+		os.Exit(1)
+	}
+}
+
+func runConfigProfileList(cmd *cobra.Command, args []string) {
+	cfg := loadConfigOrExit()
+
+	if len(cfg.Profiles) == 0 {
+		output.PrintInfo("No profiles configured.")
+		return
+	}
+
+	for name := range cfg.Profiles {
+		marker := "  "
+		if name == cfg.DefaultProfile {
+			marker = "* "
+		}
+		fmt.Printf("%s%s\n", marker, name)
+	}
+}
+
+func runConfigProfileUse(cmd *cobra.Command, args []string) {
+	cfg := loadConfigOrExit()
+	name := args[0]
+
+	if _, ok := cfg.Profiles[name]; !ok {
+		output.PrintError(fmt.Sprintf("Unknown profile: %s", name))
+		os.Exit(1)
+	}
+
+	cfg.DefaultProfile = name
+	saveConfigOrExit(cfg)
+	output.PrintSuccess(fmt.Sprintf("Default profile set to: %s", name))
+}
+
+func runConfigProfileCreate(cmd *cobra.Command, args []string) {
+	cfg := loadConfigOrExit()
+	name := args[0]
+
+	if cfg.Profiles == nil {
+		cfg.Profiles = make(map[string]config.Profile)
+	}
+	if _, exists := cfg.Profiles[name]; exists {
+		output.PrintError(fmt.Sprintf("Profile already exists: %s", name))
+		os.Exit(1)
+	}
+
+	profile := config.Profile{}
+	if profileFromCurrent {
+		profile.DefaultProvider = cfg.DefaultProvider
+		agent := cfg.Agent
+		shell := cfg.Shell
+		profile.Agent = &agent
+		profile.Shell = &shell
+		if settings, ok := cfg.Providers[cfg.DefaultProvider]; ok {
+			profile.Providers = map[string]config.ProviderSettings{cfg.DefaultProvider: settings}
+		}
+	}
+
+	cfg.Profiles[name] = profile
+	saveConfigOrExit(cfg)
+	output.PrintSuccess(fmt.Sprintf("Created profile: %s", name))
+}
+
+func runConfigProfileDelete(cmd *cobra.Command, args []string) {
+	cfg := loadConfigOrExit()
+	name := args[0]
+
+	if _, ok := cfg.Profiles[name]; !ok {
+		output.PrintError(fmt.Sprintf("Unknown profile: %s", name))
+		os.Exit(1)
+	}
+
+	delete(cfg.Profiles, name)
+	if cfg.DefaultProfile == name {
+		cfg.DefaultProfile = ""
+	}
+	saveConfigOrExit(cfg)
+	output.PrintSuccess(fmt.Sprintf("Deleted profile: %s", name))
+}
+
+func runConfigProfileCopy(cmd *cobra.Command, args []string) {
+	cfg := loadConfigOrExit()
+	src, dst := args[0], args[1]
+
+	profile, ok := cfg.Profiles[src]
+	if !ok {
+		output.PrintError(fmt.Sprintf("Unknown profile: %s", src))
+		os.Exit(1)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = make(map[string]config.Profile)
+	}
+
+	cfg.Profiles[dst] = profile
+	saveConfigOrExit(cfg)
+	output.PrintSuccess(fmt.Sprintf("Copied profile %s to %s", src, dst))
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"uhh/internal/agent"
+	"uhh/internal/config"
+	"uhh/internal/output"
+	"uhh/internal/provider"
+	"uhh/internal/session"
+	"uhh/internal/tools"
+	"uhh/internal/tui"
+
+	"github.com/spf13/cobra"
+)
+
+var lsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List saved agent sessions (alias for `session list`)",
+	Run:   runSessionList,
+}
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume <id> [prompt]",
+	Short: "Continue a saved agent session, optionally with a new prompt",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runResume,
+}
+
+var editCmd = &cobra.Command{
+	Use:   "edit <id> <msg-id> <prompt>",
+	Short: "Fork a saved session from msg-id with a new prompt and continue from there",
+	Args:  cobra.MinimumNArgs(3),
+	RunE:  runEdit,
+}
+
+func init() {
+	rootCmd.AddCommand(lsCmd)
+	rootCmd.AddCommand(resumeCmd)
+	rootCmd.AddCommand(editCmd)
+}
+
+func runResume(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	userPrompt := strings.Join(args[1:], " ")
+
+	ctx, err := session.Load(id)
+	if err != nil {
+		return fmt.Errorf("failed to load session %s: %w", id, err)
+	}
+
+	if userPrompt == "" {
+		for _, msg := range ctx.Path() {
+			fmt.Printf("[%s] (%s) %s\n", msg.ID, msg.Role, msg.Content)
+		}
+		output.PrintInfo(fmt.Sprintf("Pass a prompt to continue this session, e.g. `uhh resume %s <prompt>`.", id))
+		return nil
+	}
+
+	a, err := bootstrapAgent(ctx)
+	if err != nil {
+		return err
+	}
+
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	events, err := a.RunStream(sigCtx, userPrompt)
+	if err != nil {
+		return err
+	}
+	return drainAgentEvents(sigCtx, a, id, events)
+}
+
+func runEdit(cmd *cobra.Command, args []string) error {
+	id, msgID := args[0], args[1]
+	newPrompt := strings.Join(args[2:], " ")
+
+	ctx, err := session.Load(id)
+	if err != nil {
+		return fmt.Errorf("failed to load session %s: %w", id, err)
+	}
+
+	if _, err := ctx.Fork(msgID, newPrompt); err != nil {
+		return fmt.Errorf("failed to fork from %s: %w", msgID, err)
+	}
+
+	a, err := bootstrapAgent(ctx)
+	if err != nil {
+		return err
+	}
+
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// The fork above already appended the edited prompt as the new Head, so
+	// continue from there rather than adding another user message.
+	events, err := a.ContinueStream(sigCtx)
+	if err != nil {
+		return err
+	}
+	return drainAgentEvents(sigCtx, a, id, events)
+}
+
+// bootstrapAgent wires up an Agent around sessCtx using the active config's
+// provider and tools, the same setup runAgentMode does for a fresh session,
+// reused here for resume/edit's existing ones.
+func bootstrapAgent(sessCtx *agent.Context) (*agent.Agent, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	providerName := cfg.GetActiveProvider()
+	providerSettings, ok := cfg.GetProviderSettings(providerName)
+	if !ok {
+		return nil, fmt.Errorf("unknown provider: %s", providerName)
+	}
+	if providerSettings.Command != "" {
+		if err := provider.RegisterCommand(providerName, providerSettings.Command, providerSettings.Args, providerSettings.Env, providerSettings.Socket); err != nil {
+			return nil, fmt.Errorf("failed to start provider plugin %s: %w", providerName, err)
+		}
+	}
+
+	p, err := provider.GetAndInitialize(providerName, provider.Config{
+		APIKey:      providerSettings.APIKey,
+		Model:       getModelOrDefault(providerSettings.Model, providerName),
+		BaseURL:     providerSettings.BaseURL,
+		Temperature: providerSettings.Temperature,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize provider: %w", err)
+	}
+	if !p.SupportsToolCalling() {
+		return nil, fmt.Errorf("provider %s does not support tool calling", p.Name())
+	}
+
+	toolRegistry := tools.DefaultRegistryWithConfig(cfg.Shell)
+	for _, err := range tools.LoadExternal(toolRegistry) {
+		output.PrintWarn(fmt.Sprintf("tool plugin: %v", err))
+	}
+
+	agentConfig := agent.Config{
+		AutoApprove:   autoApproveFlag || cfg.Agent.AutoApprove,
+		MaxIterations: cfg.Agent.MaxIterations,
+		Temperature:   cfg.Providers[providerName].Temperature,
+		StopWords:     cfg.StopWords,
+		Pricing:       pricingTable(cfg),
+	}
+
+	a := agent.New(p, toolRegistry, agentConfig)
+	a.SetContext(sessCtx)
+	if !agentConfig.AutoApprove {
+		a.SetConfirmFunc(tui.ConfirmToolExecution)
+		a.SetFileWriteConfirmFunc(tui.ConfirmFileWrite)
+	}
+
+	return a, nil
+}
+
+// drainAgentEvents renders an Agent's streamed events like runAgentMode
+// does, then saves the (now-updated) session back under id regardless of
+// whether the run finished cleanly, so a cancelled resume/edit can be
+// picked back up later.
+func drainAgentEvents(ctx context.Context, a *agent.Agent, id string, events <-chan agent.AgentEvent) error {
+	var result *agent.Result
+	for event := range events {
+		switch event.Type {
+		case agent.EventAssistantDelta:
+			fmt.Print(output.CommandString(event.Text))
+		case agent.EventToolCallStart:
+			output.PrintTool(event.ToolName)
+			fmt.Println(event.ToolInput)
+		case agent.EventToolCallResult:
+			if event.Err != nil {
+				output.PrintToolError(event.ToolName, event.Err)
+			}
+		case agent.EventFinal:
+			result = event.Result
+		}
+	}
+	fmt.Println()
+
+	if err := session.Save(id, a.Context()); err != nil {
+		output.PrintWarn(fmt.Sprintf("failed to save session %s: %v", id, err))
+	}
+
+	if result == nil {
+		return ctx.Err()
+	}
+	if result.Usage.TotalTokens > 0 {
+		output.PrintDim(fmt.Sprintf("Used %d tools in %d iterations%s", len(result.ToolsUsed), result.Iterations, formatUsage(result.Usage)))
+	}
+	return result.Error
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"uhh/internal/output"
+	"uhh/internal/session"
+
+	"github.com/spf13/cobra"
+)
+
+var sessionCmd = &cobra.Command{
+	Use:   "session",
+	Short: "List, inspect, and manage saved conversation sessions",
+}
+
+var sessionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved sessions",
+	Run:   runSessionList,
+}
+
+var sessionViewCmd = &cobra.Command{
+	Use:   "view <id>",
+	Short: "Show a session's active conversation branch",
+	Args:  cobra.ExactArgs(1),
+	Run:   runSessionView,
+}
+
+var sessionCheckoutCmd = &cobra.Command{
+	Use:   "checkout <id> <message-id>",
+	Short: "Switch a session's active branch to a given message",
+	Args:  cobra.ExactArgs(2),
+	Run:   runSessionCheckout,
+}
+
+var sessionRmCmd = &cobra.Command{
+	Use:   "rm <id>",
+	Short: "Delete a saved session",
+	Args:  cobra.ExactArgs(1),
+	Run:   runSessionRm,
+}
+
+func init() {
+	sessionCmd.AddCommand(sessionListCmd)
+	sessionCmd.AddCommand(sessionViewCmd)
+	sessionCmd.AddCommand(sessionCheckoutCmd)
+	sessionCmd.AddCommand(sessionRmCmd)
+	rootCmd.AddCommand(sessionCmd)
+}
+
+func runSessionList(cmd *cobra.Command, args []string) {
+	infos, err := session.List()
+	if err != nil {
+		output.PrintError(fmt.Sprintf("Failed to list sessions: %v", err))
+		os.Exit(1)
+	}
+
+	if len(infos) == 0 {
+		output.PrintInfo("No saved sessions.")
+		return
+	}
+
+	for _, info := range infos {
+		fmt.Printf("%s  %s  %s\n", info.ID, info.UpdatedAt.Format("2006-01-02 15:04:05"), info.Preview)
+	}
+}
+
+func runSessionView(cmd *cobra.Command, args []string) {
+	ctx, err := session.Load(args[0])
+	if err != nil {
+		output.PrintError(fmt.Sprintf("Failed to load session %s: %v", args[0], err))
+		os.Exit(1)
+	}
+
+	for _, msg := range ctx.Path() {
+		fmt.Printf("[%s] (%s) %s\n", msg.ID, msg.Role, msg.Content)
+	}
+}
+
+func runSessionCheckout(cmd *cobra.Command, args []string) {
+	id, msgID := args[0], args[1]
+
+	ctx, err := session.Load(id)
+	if err != nil {
+		output.PrintError(fmt.Sprintf("Failed to load session %s: %v", id, err))
+		os.Exit(1)
+	}
+
+	if err := ctx.Checkout(msgID); err != nil {
+		output.PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	if err := session.Save(id, ctx); err != nil {
+		output.PrintError(fmt.Sprintf("Failed to save session %s: %v", id, err))
+		os.Exit(1)
+	}
+
+	output.PrintSuccess(fmt.Sprintf("Checked out %s in session %s.", msgID, id))
+}
+
+func runSessionRm(cmd *cobra.Command, args []string) {
+	if err := session.Remove(args[0]); err != nil {
+		output.PrintError(fmt.Sprintf("Failed to remove session %s: %v", args[0], err))
+		os.Exit(1)
+	}
+
+	output.PrintSuccess(fmt.Sprintf("Removed session %s.", args[0]))
+}
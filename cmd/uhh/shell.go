@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"uhh/internal/output"
+	"uhh/internal/shell"
+
+	"github.com/spf13/cobra"
+)
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Manage shell integration (completions, command-not-found suggestions, hotkeys)",
+}
+
+var shellInstallCmd = &cobra.Command{
+	Use:   "install [bash|zsh|fish|powershell]",
+	Short: "Install shell integration for the detected (or given) shell",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   runShellInstall,
+}
+
+var shellUninstallCmd = &cobra.Command{
+	Use:   "uninstall [bash|zsh|fish|powershell]",
+	Short: "Remove previously installed shell integration",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   runShellUninstall,
+}
+
+var shellHookCmd = &cobra.Command{
+	Use:   "hook <bash|zsh|fish|powershell>",
+	Short: "Print the shell integration snippet for eval'ing directly, e.g. eval \"$(uhh shell hook bash)\"",
+	Args:  cobra.ExactArgs(1),
+	Run:   runShellHook,
+}
+
+func init() {
+	shellCmd.AddCommand(shellInstallCmd)
+	shellCmd.AddCommand(shellUninstallCmd)
+	shellCmd.AddCommand(shellHookCmd)
+	rootCmd.AddCommand(shellCmd)
+}
+
+func targetShell(args []string) string {
+	if len(args) > 0 {
+		return shell.NormalizeShellName(args[0])
+	}
+	return shell.DetermineShell(shellFlag, os.Getenv("UHH_SHELL"))
+}
+
+func runShellInstall(cmd *cobra.Command, args []string) {
+	target := targetShell(args)
+	if target == shell.Unknown {
+		output.PrintError("Could not detect your shell; pass it explicitly, e.g. `uhh shell install zsh`.")
+		os.Exit(1)
+	}
+
+	if err := shell.InstallIntegration(target, shell.IntegrationOptions{}); err != nil {
+		output.PrintError(fmt.Sprintf("Failed to install %s integration: %v", shell.GetShellDisplayName(target), err))
+		os.Exit(1)
+	}
+
+	output.PrintSuccess(fmt.Sprintf("Installed %s integration. Restart your shell (or source its rc file) to pick it up.", shell.GetShellDisplayName(target)))
+}
+
+func runShellUninstall(cmd *cobra.Command, args []string) {
+	target := targetShell(args)
+	if target == shell.Unknown {
+		output.PrintError("Could not detect your shell; pass it explicitly, e.g. `uhh shell uninstall zsh`.")
+		os.Exit(1)
+	}
+
+	if err := shell.UninstallIntegration(target); err != nil {
+		output.PrintError(fmt.Sprintf("Failed to uninstall %s integration: %v", shell.GetShellDisplayName(target), err))
+		os.Exit(1)
+	}
+
+	output.PrintSuccess(fmt.Sprintf("Removed %s integration.", shell.GetShellDisplayName(target)))
+}
+
+func runShellHook(cmd *cobra.Command, args []string) {
+	target := shell.NormalizeShellName(args[0])
+	if err := shell.PrintIntegrationSnippet(target, os.Stdout); err != nil {
+		output.PrintError(err.Error())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"uhh/internal/filebackup"
+	"uhh/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo [path]",
+	Short: "Restore a file to its contents before the last agent write",
+	Long:  "Restore path to the snapshot FileWriteTool took just before its most recent overwrite. With no path, restores the most recently overwritten file.",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   runUndo,
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+}
+
+func runUndo(cmd *cobra.Command, args []string) {
+	var rec filebackup.Record
+	var found bool
+	var err error
+
+	if len(args) == 1 {
+		rec, found, err = filebackup.Latest(args[0])
+	} else {
+		var records []filebackup.Record
+		records, err = filebackup.List()
+		if err == nil && len(records) > 0 {
+			rec, found = records[0], true
+		}
+	}
+
+	if err != nil {
+		output.PrintError(fmt.Sprintf("Failed to look up backups: %v", err))
+		os.Exit(1)
+	}
+	if !found {
+		if len(args) == 1 {
+			output.PrintWarn(fmt.Sprintf("No backup found for %s.", args[0]))
+		} else {
+			output.PrintWarn("No backups recorded yet.")
+		}
+		return
+	}
+
+	if err := filebackup.Restore(rec); err != nil {
+		output.PrintError(fmt.Sprintf("Failed to restore %s: %v", rec.OriginalPath, err))
+		os.Exit(1)
+	}
+
+	output.PrintSuccess(fmt.Sprintf("Restored %s from backup taken %s.", filepath.Base(rec.OriginalPath), rec.Timestamp.Format("2006-01-02 15:04:05")))
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"uhh/internal/history"
+	"uhh/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show cumulative token usage and estimated cost",
+	Run:   runUsage,
+}
+
+func init() {
+	rootCmd.AddCommand(usageCmd)
+}
+
+func runUsage(cmd *cobra.Command, args []string) {
+	today, err := history.UsageToday()
+	if err != nil {
+		output.PrintError(fmt.Sprintf("Failed to read usage: %v", err))
+		return
+	}
+	month, err := history.UsageThisMonth()
+	if err != nil {
+		output.PrintError(fmt.Sprintf("Failed to read usage: %v", err))
+		return
+	}
+	allTime, err := history.UsageAllTime()
+	if err != nil {
+		output.PrintError(fmt.Sprintf("Failed to read usage: %v", err))
+		return
+	}
+
+	printUsageTotal("Today", today)
+	printUsageTotal("This month", month)
+	printUsageTotal("All time", allTime)
+}
+
+func printUsageTotal(label string, t history.UsageTotal) {
+	fmt.Printf("%-10s %8d tokens  (%6d prompt / %6d completion)  $%.4f\n",
+		label, t.TotalTokens, t.PromptTokens, t.CompletionTokens, t.CostUSD)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+
+	"uhh/internal/config"
+	"uhh/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show the current configuration",
+	Run:   runConfigShow,
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) {
+	cfg, err := config.Load()
+	if err != nil {
+		output.PrintError(fmt.Sprintf("Failed to load config: %v", err))
+		return
+	}
+
+	fmt.Printf("Default Provider: %s\n", cfg.DefaultProvider)
+	fmt.Printf("Auto-Approve: %v\n", cfg.Agent.AutoApprove)
+	fmt.Printf("Max Iterations: %d\n", cfg.Agent.MaxIterations)
+	fmt.Println()
+	fmt.Println("Providers:")
+	for _, name := range config.AllProviders() {
+		settings, ok := cfg.Providers[name]
+		status := "disabled"
+		if ok && settings.Enabled {
+			status = "enabled"
+		}
+		hasKey := "no key"
+		if settings.APIKey != "" {
+			hasKey = "key set"
+		}
+		fmt.Printf("  %s: %s (%s, model: %s)\n", name, status, hasKey, settings.Model)
+	}
+}
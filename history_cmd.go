@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+
+	"uhh/internal/history"
+	"uhh/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var historyListLimit int
+
+var (
+	historyCmd = &cobra.Command{
+		Use:   "history",
+		Short: "View or manage prompt history",
+	}
+
+	historyListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List recent history entries",
+		Run:   runHistoryList,
+	}
+
+	historyShowCmd = &cobra.Command{
+		Use:   "show",
+		Short: "Show the most recent history entry in full",
+		Run:   runHistoryShow,
+	}
+
+	historyClearCmd = &cobra.Command{
+		Use:   "clear",
+		Short: "Clear all prompt history",
+		Run:   runHistoryClear,
+	}
+)
+
+func init() {
+	historyListCmd.Flags().IntVarP(&historyListLimit, "limit", "n", 10, "Number of entries to list")
+
+	historyCmd.AddCommand(historyListCmd)
+	historyCmd.AddCommand(historyShowCmd)
+	historyCmd.AddCommand(historyClearCmd)
+}
+
+func runHistoryList(cmd *cobra.Command, args []string) {
+	entries := history.LoadRecentEntries(historyListLimit)
+	if len(entries) == 0 {
+		output.PrintInfo("No history yet.")
+		return
+	}
+
+	for _, entry := range entries {
+		fmt.Printf("%s  [%s]  %s\n", entry.Time.Format("2006-01-02 15:04:05"), entry.Shell, entry.Prompt)
+	}
+}
+
+func runHistoryShow(cmd *cobra.Command, args []string) {
+	entries := history.LoadRecentEntries(1)
+	if len(entries) == 0 {
+		output.PrintInfo("No history yet.")
+		return
+	}
+
+	entry := entries[0]
+	fmt.Printf("Time:   %s\n", entry.Time.Format("2006-01-02 15:04:05"))
+	fmt.Printf("Shell:  %s\n", entry.Shell)
+	fmt.Printf("Prompt: %s\n", entry.Prompt)
+	fmt.Printf("Output: %s\n", entry.Output)
+}
+
+func runHistoryClear(cmd *cobra.Command, args []string) {
+	if err := history.Clear(); err != nil {
+		output.PrintError(fmt.Sprintf("Failed to clear history: %v", err))
+		return
+	}
+	output.PrintSuccess("History cleared.")
+}
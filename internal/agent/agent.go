@@ -15,11 +15,25 @@ import (
 // ConfirmFunc is called to confirm tool execution
 type ConfirmFunc func(toolName, description, command string) (bool, error)
 
+// FileWriteConfirmFunc is called to confirm a file_write tool call, given
+// the resolved path, a unified diff of the proposed change, and a summary
+// stats line (e.g. "+3/-1 lines"). If unset, file_write falls back to the
+// generic ConfirmFunc like any other tool.
+type FileWriteConfirmFunc func(path, diff, stats string) (bool, error)
+
 // Config contains agent configuration
 type Config struct {
 	AutoApprove   bool
 	MaxIterations int
 	Temperature   float64
+
+	// StopWords are passed to the provider as stop sequences, typically set
+	// from an active profile's config.Profile.StopWords.
+	StopWords []string
+
+	// Pricing, if set, is used to estimate Usage.CostUSD for the provider's
+	// model on each round trip. A nil/empty table just leaves CostUSD at 0.
+	Pricing provider.PricingTable
 }
 
 // DefaultConfig returns default agent configuration
@@ -33,55 +47,202 @@ func DefaultConfig() Config {
 
 // Agent represents an AI agent with tool-calling capabilities
 type Agent struct {
-	provider  provider.Provider
-	tools     *tools.Registry
-	config    Config
-	context   *Context
-	confirmFn ConfirmFunc
+	provider           provider.Provider
+	tools              *tools.Registry
+	config             Config
+	context            *Context
+	confirmFn          ConfirmFunc
+	dangerousConfirmFn ConfirmFunc
+	fileWriteConfirmFn FileWriteConfirmFunc
+}
+
+// Usage records token accounting for a single provider round trip (or, on
+// Result, the sum across every round trip in the run), taken from
+// langchaingo's ContentChoice.GenerationInfo.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+	CostUSD          float64
+}
+
+// Add returns the sum of u and other, for accumulating each iteration's
+// Usage into Result.Usage.
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		PromptTokens:     u.PromptTokens + other.PromptTokens,
+		CompletionTokens: u.CompletionTokens + other.CompletionTokens,
+		TotalTokens:      u.TotalTokens + other.TotalTokens,
+		CostUSD:          u.CostUSD + other.CostUSD,
+	}
 }
 
 // ToolExecution records a tool execution
 type ToolExecution struct {
-	ToolName  string
-	Input     string
-	Output    string
-	Approved  bool
-	Skipped   bool
-	Duration  time.Duration
-	Error     error
+	ToolName string
+	Input    string
+	Output   string
+	Approved bool
+	Skipped  bool
+	Duration time.Duration
+	Error    error
+
+	// Usage is the token usage of the round trip that produced this tool
+	// call. Multiple ToolExecutions from the same iteration share the same
+	// Usage, since one GenerateContent response can carry several tool calls.
+	Usage Usage
 }
 
 // Result contains the result of an agent run
 type Result struct {
-	FinalAnswer    string
-	ToolsUsed      []ToolExecution
-	Iterations     int
-	Success        bool
-	Error          error
+	FinalAnswer string
+	ToolsUsed   []ToolExecution
+	Iterations  int
+	Success     bool
+	Error       error
+
+	// Usage is the sum of every round trip's token usage across the run.
+	Usage Usage
 }
 
 // New creates a new agent
 func New(p provider.Provider, t *tools.Registry, cfg Config) *Agent {
+	ctx := NewContext("")
+	ctx.Tokenizer = DefaultTokenizer(p.Name())
+
 	return &Agent{
 		provider: p,
 		tools:    t,
 		config:   cfg,
-		context:  NewContext(""),
+		context:  ctx,
+	}
+}
+
+// Profile is a named agent persona: a system prompt and the subset of t's
+// tools it's allowed to call, plus optional default provider/model/
+// temperature for the cmd layer to resolve before calling NewWithProfile.
+// See config.AgentProfile for the on-disk representation this is built
+// from.
+type Profile struct {
+	Name         string
+	SystemPrompt string
+
+	// Tools allow-lists tool names this agent may call. Empty means every
+	// tool in the registry passed to NewWithProfile is available.
+	Tools []string
+
+	Provider    string
+	Model       string
+	Temperature float64
+}
+
+// NewWithProfile creates an agent restricted to profile's tool allow-list
+// (via t.Subset), with profile's system prompt applied if set. Use New
+// directly for the unrestricted, all-tools behavior.
+func NewWithProfile(p provider.Provider, t *tools.Registry, cfg Config, profile Profile) *Agent {
+	a := New(p, t.Subset(profile.Tools), cfg)
+	if profile.SystemPrompt != "" {
+		a.SetSystemPrompt(profile.SystemPrompt)
 	}
+	return a
 }
 
-// SetConfirmFunc sets the confirmation function for dangerous operations
+// SetConfirmFunc sets the confirmation function used for tool executions
+// that require approval.
 func (a *Agent) SetConfirmFunc(fn ConfirmFunc) {
 	a.confirmFn = fn
 }
 
+// SetDangerousConfirmFunc sets a confirmation function used specifically for
+// tools.SafetyLevelDangerous tool calls, overriding ConfirmFunc for those. If
+// unset, dangerous tool calls fall back to ConfirmFunc like everything else.
+func (a *Agent) SetDangerousConfirmFunc(fn ConfirmFunc) {
+	a.dangerousConfirmFn = fn
+}
+
+// SetFileWriteConfirmFunc sets a diff-aware confirmation function used
+// specifically for the file_write tool, overriding ConfirmFunc for it. If
+// unset, file_write falls back to ConfirmFunc like everything else.
+func (a *Agent) SetFileWriteConfirmFunc(fn FileWriteConfirmFunc) {
+	a.fileWriteConfirmFn = fn
+}
+
+// confirmToolCall confirms tool, special-casing file_write to show a diff
+// preview when a FileWriteConfirmFunc is set.
+func (a *Agent) confirmToolCall(tool tools.Tool, input tools.Input) (bool, error) {
+	if fw, ok := tool.(*tools.FileWriteTool); ok && a.fileWriteConfirmFn != nil {
+		path, diff, stats, err := fw.Preview(input)
+		if err != nil {
+			return false, err
+		}
+		return a.fileWriteConfirmFn(path, diff, stats)
+	}
+
+	confirmFn := a.confirmFuncFor(tool)
+	if confirmFn == nil {
+		return true, nil
+	}
+
+	description := fmt.Sprintf("Execute %s tool", tool.Name())
+	return confirmFn(tool.Name(), description, input.Raw)
+}
+
+// confirmFuncFor returns the confirmation function that should gate the
+// given tool's execution.
+func (a *Agent) confirmFuncFor(tool tools.Tool) ConfirmFunc {
+	if tool.SafetyLevel() == tools.SafetyLevelDangerous && a.dangerousConfirmFn != nil {
+		return a.dangerousConfirmFn
+	}
+	return a.confirmFn
+}
+
+// usageFrom reads choice's token usage and prices it against a.config.Pricing
+// for a.provider.Model(), returning a zero-cost Usage if no pricing entry
+// exists for that model.
+func (a *Agent) usageFrom(choice *llms.ContentChoice) Usage {
+	prompt, completion, total := provider.UsageFromGenerationInfo(choice.GenerationInfo)
+	return Usage{
+		PromptTokens:     prompt,
+		CompletionTokens: completion,
+		TotalTokens:      total,
+		CostUSD:          a.config.Pricing.Cost(a.provider.Model(), prompt, completion),
+	}
+}
+
+// callOptions builds the base llms.CallOption set shared by Run and
+// RunStream, before tool-specific options are appended.
+func (a *Agent) callOptions() []llms.CallOption {
+	opts := []llms.CallOption{llms.WithTemperature(a.config.Temperature)}
+	if len(a.config.StopWords) > 0 {
+		opts = append(opts, llms.WithStopWords(a.config.StopWords))
+	}
+	return opts
+}
+
 // SetSystemPrompt sets the system prompt for the agent
 func (a *Agent) SetSystemPrompt(prompt string) {
 	a.context.SystemPrompt = prompt
 }
 
-// Run executes the agent loop with the given user prompt
-func (a *Agent) Run(ctx context.Context, userPrompt string) (*Result, error) {
+// RunOptions configures an otherwise-optional behavior of Run. The zero
+// value runs exactly as before StreamFunc was introduced.
+type RunOptions struct {
+	// StreamFunc, if set, is invoked with each chunk of assistant text as it
+	// arrives from the provider, letting callers render tokens live without
+	// switching to the channel-based RunStream API. toolCallDelta is reserved
+	// for providers that stream incremental tool-call arguments; none do yet,
+	// so it's always nil today.
+	StreamFunc func(chunk []byte, toolCallDelta *llms.ToolCall) error
+}
+
+// Run executes the agent loop with the given user prompt. opts is variadic
+// so existing callers are unaffected; at most the first RunOptions is used.
+func (a *Agent) Run(ctx context.Context, userPrompt string, opts ...RunOptions) (*Result, error) {
+	var ro RunOptions
+	if len(opts) > 0 {
+		ro = opts[0]
+	}
+
 	result := &Result{
 		ToolsUsed:  make([]ToolExecution, 0),
 		Iterations: 0,
@@ -96,19 +257,32 @@ func (a *Agent) Run(ctx context.Context, userPrompt string) (*Result, error) {
 	for i := 0; i < a.config.MaxIterations; i++ {
 		result.Iterations = i + 1
 
+		// Compact the context before each round trip - tool-heavy turns can
+		// blow past a token budget long before they'd hit any message-count
+		// limit, so this is checked every iteration rather than once upfront.
+		if err := a.context.Compact(ctx, a.provider); err != nil {
+			result.Error = fmt.Errorf("failed to compact context: %w", err)
+			return result, result.Error
+		}
+
 		// Build messages
-		messages := a.context.ToLangchainMessages()
+		messages := a.context.ToLangchainMessagesForProvider(a.provider.Name())
 
 		// Call LLM with tools
-		opts := []llms.CallOption{
-			llms.WithTemperature(a.config.Temperature),
-		}
-
+		opts := a.callOptions()
 		if len(availableTools) > 0 {
 			opts = append(opts, llms.WithTools(availableTools))
 		}
 
-		response, err := a.provider.GenerateContent(ctx, messages, opts...)
+		var response *llms.ContentResponse
+		var err error
+		if ro.StreamFunc != nil {
+			response, err = a.provider.GenerateContentStream(ctx, messages, opts, func(ctx context.Context, chunk []byte) error {
+				return ro.StreamFunc(chunk, nil)
+			})
+		} else {
+			response, err = a.provider.GenerateContent(ctx, messages, opts...)
+		}
 		if err != nil {
 			result.Error = err
 			return result, err
@@ -120,6 +294,8 @@ func (a *Agent) Run(ctx context.Context, userPrompt string) (*Result, error) {
 		}
 
 		choice := response.Choices[0]
+		usage := a.usageFrom(choice)
+		result.Usage = result.Usage.Add(usage)
 
 		// Check if there are tool calls
 		if len(choice.ToolCalls) > 0 {
@@ -129,6 +305,7 @@ func (a *Agent) Run(ctx context.Context, userPrompt string) (*Result, error) {
 			// Process tool calls
 			for _, toolCall := range choice.ToolCalls {
 				execution := a.executeToolCall(ctx, toolCall)
+				execution.Usage = usage
 				result.ToolsUsed = append(result.ToolsUsed, execution)
 
 				// Add tool result to context
@@ -180,14 +357,20 @@ func (a *Agent) executeToolCall(ctx context.Context, toolCall llms.ToolCall) Too
 		return execution
 	}
 
+	// Parse input
+	var parsed map[string]interface{}
+	json.Unmarshal([]byte(toolCall.FunctionCall.Arguments), &parsed)
+
+	input := tools.Input{
+		Raw:    toolCall.FunctionCall.Arguments,
+		Parsed: parsed,
+	}
+
 	// Check if confirmation is needed
 	needsConfirmation := tool.RequiresConfirmation() && !a.config.AutoApprove
 
-	if needsConfirmation && a.confirmFn != nil {
-		// Build description for confirmation
-		description := fmt.Sprintf("Execute %s tool", tool.Name())
-
-		approved, err := a.confirmFn(tool.Name(), description, toolCall.FunctionCall.Arguments)
+	if needsConfirmation {
+		approved, err := a.confirmToolCall(tool, input)
 		if err != nil {
 			execution.Error = err
 			execution.Duration = time.Since(start)
@@ -204,15 +387,6 @@ func (a *Agent) executeToolCall(ctx context.Context, toolCall llms.ToolCall) Too
 		execution.Approved = true
 	}
 
-	// Parse input
-	var parsed map[string]interface{}
-	json.Unmarshal([]byte(toolCall.FunctionCall.Arguments), &parsed)
-
-	input := tools.Input{
-		Raw:    toolCall.FunctionCall.Arguments,
-		Parsed: parsed,
-	}
-
 	// Execute tool
 	output, err := tool.Execute(ctx, input)
 	execution.Duration = time.Since(start)
@@ -234,6 +408,164 @@ func (a *Agent) executeToolCall(ctx context.Context, toolCall llms.ToolCall) Too
 	return execution
 }
 
+// AgentEventType identifies the kind of event emitted during a streaming run
+type AgentEventType int
+
+const (
+	// EventAssistantDelta carries a chunk of assistant text as it streams in
+	EventAssistantDelta AgentEventType = iota
+	// EventToolCallStart fires right before a tool executes
+	EventToolCallStart
+	// EventToolCallResult fires once a tool call finishes (success or error)
+	EventToolCallResult
+	// EventIterationBoundary fires at the start of each agent loop iteration
+	EventIterationBoundary
+	// EventFinal fires once with the completed Result, then the channel closes
+	EventFinal
+)
+
+// AgentEvent is a single event emitted by Agent.RunStream
+type AgentEvent struct {
+	Type       AgentEventType
+	Text       string // assistant text delta (EventAssistantDelta)
+	ToolName   string // EventToolCallStart / EventToolCallResult
+	ToolInput  string // EventToolCallStart
+	ToolOutput string // EventToolCallResult
+	Iteration  int    // EventIterationBoundary
+	Result     *Result
+	Err        error
+}
+
+// RunStream executes the agent loop like Run, but emits AgentEvents as it
+// goes so the caller can render assistant text and tool activity live and
+// cancel cleanly via ctx (e.g. on SIGINT) without losing the accumulated
+// Result.
+func (a *Agent) RunStream(ctx context.Context, userPrompt string) (<-chan AgentEvent, error) {
+	return a.runStream(ctx, userPrompt, true)
+}
+
+// ContinueStream is RunStream for a context whose Head already points at
+// the message to respond to - e.g. right after Context.Fork, or a session
+// loaded from disk via session.Load - so it drives the loop from the
+// current Head as-is instead of adding another user message first.
+func (a *Agent) ContinueStream(ctx context.Context) (<-chan AgentEvent, error) {
+	return a.runStream(ctx, "", false)
+}
+
+func (a *Agent) runStream(ctx context.Context, userPrompt string, addUserMessage bool) (<-chan AgentEvent, error) {
+	events := make(chan AgentEvent)
+
+	go func() {
+		defer close(events)
+
+		result := &Result{
+			ToolsUsed:  make([]ToolExecution, 0),
+			Iterations: 0,
+		}
+
+		if addUserMessage {
+			a.context.AddUserMessage(userPrompt)
+		}
+		availableTools := a.tools.ToLangchainTools()
+
+		for i := 0; i < a.config.MaxIterations; i++ {
+			if ctx.Err() != nil {
+				result.Error = ctx.Err()
+				events <- AgentEvent{Type: EventFinal, Result: result, Err: ctx.Err()}
+				return
+			}
+
+			result.Iterations = i + 1
+			events <- AgentEvent{Type: EventIterationBoundary, Iteration: i + 1}
+
+			if err := a.context.Compact(ctx, a.provider); err != nil {
+				result.Error = fmt.Errorf("failed to compact context: %w", err)
+				events <- AgentEvent{Type: EventFinal, Result: result, Err: result.Error}
+				return
+			}
+
+			messages := a.context.ToLangchainMessagesForProvider(a.provider.Name())
+			opts := a.callOptions()
+			if len(availableTools) > 0 {
+				opts = append(opts, llms.WithTools(availableTools))
+			}
+
+			response, err := a.provider.GenerateContentStream(ctx, messages, opts, func(ctx context.Context, chunk []byte) error {
+				events <- AgentEvent{Type: EventAssistantDelta, Text: string(chunk)}
+				return nil
+			})
+			if err != nil {
+				result.Error = err
+				events <- AgentEvent{Type: EventFinal, Result: result, Err: err}
+				return
+			}
+			if len(response.Choices) == 0 {
+				result.Error = fmt.Errorf("no response from LLM")
+				events <- AgentEvent{Type: EventFinal, Result: result, Err: result.Error}
+				return
+			}
+
+			choice := response.Choices[0]
+			usage := a.usageFrom(choice)
+			result.Usage = result.Usage.Add(usage)
+
+			if len(choice.ToolCalls) > 0 {
+				a.context.AddAssistantMessageWithToolCalls(choice.Content, choice.ToolCalls)
+
+				for _, toolCall := range choice.ToolCalls {
+					events <- AgentEvent{
+						Type:      EventToolCallStart,
+						ToolName:  toolCall.FunctionCall.Name,
+						ToolInput: toolCall.FunctionCall.Arguments,
+					}
+
+					execution := a.executeToolCall(ctx, toolCall)
+					execution.Usage = usage
+					result.ToolsUsed = append(result.ToolsUsed, execution)
+
+					if execution.Error != nil {
+						a.context.AddToolResult(toolCall.ID, fmt.Sprintf("Error: %v", execution.Error))
+					} else if execution.Skipped {
+						a.context.AddToolResult(toolCall.ID, "Tool execution was skipped by user.")
+					} else {
+						a.context.AddToolResult(toolCall.ID, execution.Output)
+					}
+
+					events <- AgentEvent{
+						Type:       EventToolCallResult,
+						ToolName:   toolCall.FunctionCall.Name,
+						ToolOutput: execution.Output,
+						Err:        execution.Error,
+					}
+				}
+				continue
+			}
+
+			// No tool calls - this is the final answer. Its text was already
+			// streamed live via the handler above, so just record it.
+			if choice.Content != "" {
+				result.FinalAnswer = choice.Content
+				result.Success = true
+				a.context.AddAssistantMessage(choice.Content)
+				events <- AgentEvent{Type: EventFinal, Result: result}
+				return
+			}
+
+			if choice.StopReason == "end_turn" || choice.StopReason == "stop" {
+				result.FinalAnswer = choice.Content
+				result.Success = true
+				events <- AgentEvent{Type: EventFinal, Result: result}
+				return
+			}
+		}
+
+		result.Error = fmt.Errorf("max iterations (%d) reached", a.config.MaxIterations)
+		events <- AgentEvent{Type: EventFinal, Result: result, Err: result.Error}
+	}()
+
+	return events, nil
+}
+
 // SimpleCall makes a simple LLM call without tool support
 func (a *Agent) SimpleCall(ctx context.Context, prompt string) (string, error) {
 	return a.provider.Call(ctx, prompt, llms.WithTemperature(a.config.Temperature))
@@ -243,3 +575,16 @@ func (a *Agent) SimpleCall(ctx context.Context, prompt string) (string, error) {
 func (a *Agent) Reset() {
 	a.context.Clear()
 }
+
+// Context returns the agent's conversation context, for callers that want
+// to persist it (see internal/session) or inspect/edit its branches
+// directly.
+func (a *Agent) Context() *Context {
+	return a.context
+}
+
+// SetContext replaces the agent's conversation context, e.g. after loading
+// a previously saved session via internal/session.Load.
+func (a *Agent) SetContext(c *Context) {
+	a.context = c
+}
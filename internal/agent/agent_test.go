@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"uhh/internal/provider"
+	"uhh/internal/tools"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// stubProvider is a minimal provider.Provider that returns a single canned
+// final answer, streaming it through GenerateContentStream one byte at a
+// time so tests can assert StreamFunc actually receives live chunks rather
+// than the whole response at once.
+type stubProvider struct {
+	answer string
+
+	// generationInfo, if set, is attached to every ContentChoice returned,
+	// letting tests exercise Usage accounting without a real provider.
+	generationInfo map[string]interface{}
+}
+
+func (s *stubProvider) Name() string                          { return "stub" }
+func (s *stubProvider) DisplayName() string                   { return "Stub" }
+func (s *stubProvider) SupportsToolCalling() bool              { return true }
+func (s *stubProvider) Model() string                          { return "stub-model" }
+func (s *stubProvider) Initialize(cfg provider.Config) error   { return nil }
+
+func (s *stubProvider) LLM() llms.Model { return nil }
+
+func (s *stubProvider) Call(ctx context.Context, prompt string, opts ...llms.CallOption) (string, error) {
+	return s.answer, nil
+}
+
+func (s *stubProvider) GenerateContent(ctx context.Context, messages []llms.MessageContent, opts ...llms.CallOption) (*llms.ContentResponse, error) {
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: s.answer, GenerationInfo: s.generationInfo}}}, nil
+}
+
+func (s *stubProvider) Stream(ctx context.Context, prompt string, opts ...llms.CallOption) (<-chan provider.Chunk, error) {
+	ch := make(chan provider.Chunk)
+	close(ch)
+	return ch, nil
+}
+
+func (s *stubProvider) GenerateContentStream(ctx context.Context, messages []llms.MessageContent, opts []llms.CallOption, handler func(ctx context.Context, chunk []byte) error) (*llms.ContentResponse, error) {
+	for i := 0; i < len(s.answer); i++ {
+		if err := handler(ctx, []byte(s.answer[i:i+1])); err != nil {
+			return nil, err
+		}
+	}
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: s.answer, GenerationInfo: s.generationInfo}}}, nil
+}
+
+func TestRunPopulatesUsageFromGenerationInfo(t *testing.T) {
+	p := &stubProvider{
+		answer: "hi",
+		generationInfo: map[string]interface{}{
+			"PromptTokens":     10,
+			"CompletionTokens": 5,
+			"TotalTokens":      15,
+		},
+	}
+	cfg := Config{
+		MaxIterations: 1,
+		Pricing:       provider.PricingTable{"stub-model": {InputPer1K: 1, OutputPer1K: 2}},
+	}
+	a := New(p, tools.NewRegistry(), cfg)
+
+	result, err := a.Run(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	if result.Usage.TotalTokens != 15 || result.Usage.PromptTokens != 10 || result.Usage.CompletionTokens != 5 {
+		t.Fatalf("unexpected Usage: %+v", result.Usage)
+	}
+	wantCost := 10.0/1000*1 + 5.0/1000*2
+	if result.Usage.CostUSD != wantCost {
+		t.Fatalf("expected CostUSD %v, got %v", wantCost, result.Usage.CostUSD)
+	}
+}
+
+func TestRunStreamFuncReceivesLiveChunks(t *testing.T) {
+	p := &stubProvider{answer: "hi"}
+	a := New(p, tools.NewRegistry(), Config{MaxIterations: 1})
+
+	var received []byte
+	_, err := a.Run(context.Background(), "hello", RunOptions{
+		StreamFunc: func(chunk []byte, _ *llms.ToolCall) error {
+			received = append(received, chunk...)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if string(received) != "hi" {
+		t.Fatalf("expected StreamFunc to receive %q, got %q", "hi", received)
+	}
+}
@@ -1,82 +1,358 @@
 package agent
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"uhh/internal/provider"
+
 	"github.com/tmc/langchaingo/llms"
 )
 
-// Message represents a conversation message
+// DefaultMaxTokens is the token budget a new Context starts with, chosen to
+// leave headroom under common 8k+ context windows once the system prompt
+// and the model's own response budget are accounted for.
+const DefaultMaxTokens = 6000
+
+// Message is a single node in a conversation's branching history. Messages
+// form a DAG rather than a flat list so a user can edit an earlier message
+// and continue from there (see Context.Fork) without discarding the
+// original subtree - the edited and original messages end up as siblings
+// under the same ParentID.
 type Message struct {
+	ID        string
+	ParentID  string
 	Role      string
 	Content   string
-	ToolID    string           // For tool responses
-	ToolCalls []llms.ToolCall  // For assistant messages with tool calls
+	ToolID    string          // For tool responses
+	ToolCalls []llms.ToolCall // For assistant messages with tool calls
 }
 
-// Context manages conversation history for the agent
+// Context manages conversation history for the agent as a DAG of messages.
+// Nodes holds every message ever added, keyed by ID, so old branches
+// survive a Fork; Head is the ID of the message the conversation currently
+// continues from. ToLangchainMessages (and friends) only ever walk the
+// path from Head to the root - sibling branches stay on disk, reachable
+// again via Checkout, but out of the active conversation.
 type Context struct {
 	SystemPrompt string
-	Messages     []Message
-	MaxMessages  int
+	Nodes        map[string]*Message
+	Head         string
+	MaxTokens    int
+
+	// Tokenizer estimates token counts for EstimateTokens/Compact. It's not
+	// persisted: a loaded session gets CharTokenizer{} as a safe default
+	// until its owner (e.g. agent.New) assigns one appropriate for the
+	// active provider.
+	Tokenizer Tokenizer `json:"-"`
+
+	seq int // monotonic counter backing newID, not persisted across process restarts
 }
 
 // NewContext creates a new conversation context
 func NewContext(systemPrompt string) *Context {
 	return &Context{
 		SystemPrompt: systemPrompt,
-		Messages:     make([]Message, 0),
-		MaxMessages:  50, // Keep last 50 messages
+		Nodes:        make(map[string]*Message),
+		MaxTokens:    DefaultMaxTokens,
+		Tokenizer:    CharTokenizer{},
+	}
+}
+
+// tokenizer returns c.Tokenizer, falling back to CharTokenizer{} for a
+// Context that was JSON-decoded (Tokenizer is never persisted) or
+// zero-valued.
+func (c *Context) tokenizer() Tokenizer {
+	if c.Tokenizer == nil {
+		return CharTokenizer{}
+	}
+	return c.Tokenizer
+}
+
+// newID generates a unique message ID. It isn't required to sort
+// chronologically - ParentID links already capture order - just to avoid
+// colliding with any ID loaded from disk.
+func (c *Context) newID() string {
+	c.seq++
+	id := fmt.Sprintf("%d-%d", time.Now().UnixNano(), c.seq)
+	for {
+		if _, exists := c.Nodes[id]; !exists {
+			return id
+		}
+		c.seq++
+		id = fmt.Sprintf("%d-%d", time.Now().UnixNano(), c.seq)
 	}
 }
 
+// appendMessage adds msg as a new child of Head and moves Head to it.
+func (c *Context) appendMessage(msg Message) *Message {
+	if c.Nodes == nil {
+		c.Nodes = make(map[string]*Message)
+	}
+	msg.ID = c.newID()
+	msg.ParentID = c.Head
+	node := msg
+	c.Nodes[node.ID] = &node
+	c.Head = node.ID
+	return &node
+}
+
 // AddUserMessage adds a user message to the context
 func (c *Context) AddUserMessage(content string) {
-	c.Messages = append(c.Messages, Message{
+	c.appendMessage(Message{
 		Role:    "user",
 		Content: content,
 	})
-	c.truncate()
 }
 
 // AddAssistantMessage adds an assistant message to the context
 func (c *Context) AddAssistantMessage(content string) {
-	c.Messages = append(c.Messages, Message{
+	c.appendMessage(Message{
 		Role:    "assistant",
 		Content: content,
 	})
-	c.truncate()
 }
 
 // AddAssistantMessageWithToolCalls adds an assistant message with tool calls
 func (c *Context) AddAssistantMessageWithToolCalls(content string, toolCalls []llms.ToolCall) {
-	c.Messages = append(c.Messages, Message{
+	c.appendMessage(Message{
 		Role:      "assistant",
 		Content:   content,
 		ToolCalls: toolCalls,
 	})
-	c.truncate()
 }
 
 // AddToolResult adds a tool result to the context
 func (c *Context) AddToolResult(toolID, result string) {
-	c.Messages = append(c.Messages, Message{
+	c.appendMessage(Message{
 		Role:    "tool",
 		Content: result,
 		ToolID:  toolID,
 	})
-	c.truncate()
 }
 
-// truncate removes old messages if we exceed the limit
-func (c *Context) truncate() {
-	if len(c.Messages) > c.MaxMessages {
-		// Keep the most recent messages
-		c.Messages = c.Messages[len(c.Messages)-c.MaxMessages:]
+// Fork creates a sibling of msgID - a new message under msgID's parent -
+// with newContent, and switches Head to it. This is how an edited user
+// message gets re-prompted: the original msgID and everything built on top
+// of it stays in Nodes untouched, reachable again via Checkout, while the
+// conversation continues from the new sibling.
+func (c *Context) Fork(msgID string, newContent string) (newHeadID string, err error) {
+	original, ok := c.Nodes[msgID]
+	if !ok {
+		return "", fmt.Errorf("unknown message id: %s", msgID)
+	}
+
+	node := &Message{
+		ID:       c.newID(),
+		ParentID: original.ParentID,
+		Role:     original.Role,
+		Content:  newContent,
+	}
+	c.Nodes[node.ID] = node
+	c.Head = node.ID
+
+	return node.ID, nil
+}
+
+// Checkout moves Head to msgID, switching the active branch to whichever
+// subtree msgID belongs to.
+func (c *Context) Checkout(msgID string) error {
+	if _, ok := c.Nodes[msgID]; !ok {
+		return fmt.Errorf("unknown message id: %s", msgID)
 	}
+	c.Head = msgID
+	return nil
+}
+
+// Path returns the active branch - every message from the root down to
+// Head, in chronological order.
+func (c *Context) Path() []*Message {
+	var reversed []*Message
+	for id := c.Head; id != ""; {
+		node, ok := c.Nodes[id]
+		if !ok {
+			break
+		}
+		reversed = append(reversed, node)
+		id = node.ParentID
+	}
+
+	path := make([]*Message, len(reversed))
+	for i, node := range reversed {
+		path[len(reversed)-1-i] = node
+	}
+	return path
+}
+
+// EstimateTokens returns the active branch's estimated token cost,
+// including the system prompt, using c.Tokenizer.
+func (c *Context) EstimateTokens() int {
+	tok := c.tokenizer()
+	total := tok.CountTokens(c.SystemPrompt)
+	for _, msg := range c.Path() {
+		total += tok.CountTokens(msg.Content)
+		for _, tc := range msg.ToolCalls {
+			if tc.FunctionCall != nil {
+				total += tok.CountTokens(tc.FunctionCall.Name) + tok.CountTokens(tc.FunctionCall.Arguments)
+			}
+		}
+	}
+	return total
+}
+
+// messageUnit is a contiguous run of messages that must move together
+// during compaction: a lone user or plain assistant message, or an
+// assistant message with ToolCalls together with every tool message that
+// answers it.
+type messageUnit []*Message
+
+// messageUnits splits path into messageUnits, keeping each
+// ToolCalls-bearing assistant message glued to its tool results.
+func messageUnits(path []*Message) []messageUnit {
+	var units []messageUnit
+	for i := 0; i < len(path); i++ {
+		unit := messageUnit{path[i]}
+		if path[i].Role == "assistant" && len(path[i].ToolCalls) > 0 {
+			for i+1 < len(path) && path[i+1].Role == "tool" {
+				i++
+				unit = append(unit, path[i])
+			}
+		}
+		units = append(units, unit)
+	}
+	return units
+}
+
+func (c *Context) unitTokens(u messageUnit) int {
+	tok := c.tokenizer()
+	total := 0
+	for _, msg := range u {
+		total += tok.CountTokens(msg.Content)
+		for _, tc := range msg.ToolCalls {
+			if tc.FunctionCall != nil {
+				total += tok.CountTokens(tc.FunctionCall.Name) + tok.CountTokens(tc.FunctionCall.Arguments)
+			}
+		}
+	}
+	return total
+}
+
+// Compact asks p to summarize the oldest part of the active branch when
+// EstimateTokens() exceeds MaxTokens, replacing that prefix with a single
+// synthetic assistant "conversation so far" message so the branch fits back
+// under budget. It preserves two invariants: an assistant message's
+// ToolCalls are never separated from their tool results (see messageUnits),
+// and the most recent user turn - and everything said after it - is never
+// dropped. If nothing can be safely dropped, or the branch already fits,
+// Compact is a no-op.
+func (c *Context) Compact(ctx context.Context, p provider.Provider) error {
+	if c.MaxTokens <= 0 || c.EstimateTokens() <= c.MaxTokens {
+		return nil
+	}
+
+	path := c.Path()
+	units := messageUnits(path)
+	if len(units) <= 1 {
+		return nil
+	}
+
+	protectedFrom := len(units) - 1
+	for i := len(units) - 1; i >= 0; i-- {
+		if units[i][0].Role == "user" {
+			protectedFrom = i
+			break
+		}
+	}
+
+	systemTokens := c.tokenizer().CountTokens(c.SystemPrompt)
+	remaining := systemTokens
+	unitTokens := make([]int, len(units))
+	for i, u := range units {
+		unitTokens[i] = c.unitTokens(u)
+		remaining += unitTokens[i]
+	}
+
+	cut := 0
+	for cut < protectedFrom && remaining > c.MaxTokens {
+		remaining -= unitTokens[cut]
+		cut++
+	}
+
+	if cut == 0 {
+		return nil
+	}
+
+	var dropped []*Message
+	for _, u := range units[:cut] {
+		dropped = append(dropped, u...)
+	}
+
+	summary, err := c.summarize(ctx, p, dropped)
+	if err != nil {
+		return err
+	}
+
+	summaryMsg := &Message{
+		ID:      c.newID(),
+		Role:    "assistant",
+		Content: summary,
+	}
+	c.Nodes[summaryMsg.ID] = summaryMsg
+
+	firstKept := units[cut][0]
+	firstKept.ParentID = summaryMsg.ID
+
+	return nil
+}
+
+// summarize asks p for a short recap of dropped, the prefix Compact is
+// about to discard, so later turns can still refer back to decisions or
+// facts established earlier in the conversation.
+func (c *Context) summarize(ctx context.Context, p provider.Provider, dropped []*Message) (string, error) {
+	var b strings.Builder
+	b.WriteString("Summarize the following conversation excerpt in a few sentences, preserving any decisions, facts, or file paths that later turns might depend on:\n\n")
+	for _, msg := range dropped {
+		fmt.Fprintf(&b, "%s: %s\n", msg.Role, msg.Content)
+	}
+
+	summary, err := p.Call(ctx, b.String())
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize conversation history: %w", err)
+	}
+
+	return "Conversation so far (summarized): " + strings.TrimSpace(summary), nil
 }
 
 // ToLangchainMessages converts the context to langchaingo message format
 func (c *Context) ToLangchainMessages() []llms.MessageContent {
-	messages := make([]llms.MessageContent, 0, len(c.Messages)+1)
+	return c.toLangchainMessages("")
+}
+
+// ToLangchainMessagesForProvider is like ToLangchainMessages, but adapts
+// tool result shaping to providerName's quirks (see toLangchainMessages).
+func (c *Context) ToLangchainMessagesForProvider(providerName string) []llms.MessageContent {
+	return c.toLangchainMessages(providerName)
+}
+
+// anthropicProviderName matches provider.AnthropicProvider's Name(); kept as
+// a literal here rather than an import of uhh/internal/provider to avoid a
+// dependency the rest of this package doesn't need.
+const anthropicProviderName = "anthropic"
+
+// toLangchainMessages converts the active branch to langchaingo message
+// format. For most providers, each tool result becomes its own
+// ChatMessageTypeTool message (OpenAI's convention). Anthropic's API
+// instead requires every tool_result answering a single assistant turn's
+// tool_use blocks to be batched into one message, one content part per tool
+// call - so when providerName is "anthropic", consecutive tool-role
+// messages are merged.
+func (c *Context) toLangchainMessages(providerName string) []llms.MessageContent {
+	path := c.Path()
+	messages := make([]llms.MessageContent, 0, len(path)+1)
 
 	// Add system message first
 	if c.SystemPrompt != "" {
@@ -84,7 +360,8 @@ func (c *Context) ToLangchainMessages() []llms.MessageContent {
 	}
 
 	// Add conversation messages
-	for _, msg := range c.Messages {
+	for i := 0; i < len(path); i++ {
+		msg := path[i]
 		switch msg.Role {
 		case "user":
 			messages = append(messages, llms.TextParts(llms.ChatMessageTypeHuman, msg.Content))
@@ -106,15 +383,18 @@ func (c *Context) ToLangchainMessages() []llms.MessageContent {
 				messages = append(messages, llms.TextParts(llms.ChatMessageTypeAI, msg.Content))
 			}
 		case "tool":
-			// Tool results are added as part of the conversation
+			parts := []llms.ContentPart{llms.ToolCallResponse{ToolCallID: msg.ToolID, Content: msg.Content}}
+
+			if providerName == anthropicProviderName {
+				for i+1 < len(path) && path[i+1].Role == "tool" {
+					i++
+					parts = append(parts, llms.ToolCallResponse{ToolCallID: path[i].ToolID, Content: path[i].Content})
+				}
+			}
+
 			messages = append(messages, llms.MessageContent{
-				Role: llms.ChatMessageTypeTool,
-				Parts: []llms.ContentPart{
-					llms.ToolCallResponse{
-						ToolCallID: msg.ToolID,
-						Content:    msg.Content,
-					},
-				},
+				Role:  llms.ChatMessageTypeTool,
+				Parts: parts,
 			})
 		}
 	}
@@ -122,20 +402,42 @@ func (c *Context) ToLangchainMessages() []llms.MessageContent {
 	return messages
 }
 
-// Clear clears all messages from the context
+// Clear discards all messages from the context, starting a fresh DAG.
 func (c *Context) Clear() {
-	c.Messages = make([]Message, 0)
+	c.Nodes = make(map[string]*Message)
+	c.Head = ""
 }
 
-// LastMessage returns the last message in the context
+// LastMessage returns the head message of the active branch
 func (c *Context) LastMessage() *Message {
-	if len(c.Messages) == 0 {
-		return nil
-	}
-	return &c.Messages[len(c.Messages)-1]
+	return c.Nodes[c.Head]
 }
 
-// MessageCount returns the number of messages in the context
+// MessageCount returns the number of messages on the active branch
 func (c *Context) MessageCount() int {
-	return len(c.Messages)
+	return len(c.Path())
+}
+
+// Save persists the full DAG (every branch, not just the active one) to
+// path as JSON, so a session can be resumed, listed, or pruned later by the
+// `uhh session` subcommands.
+func (c *Context) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// LoadContext reads a Context previously written by Save.
+func LoadContext(path string) (*Context, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Context
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
 }
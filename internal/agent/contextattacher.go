@@ -0,0 +1,243 @@
+package agent
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"uhh/internal/config"
+
+	"golang.org/x/term"
+)
+
+const (
+	// maxAttachedFiles caps how many @-referenced files (including glob
+	// expansions) get attached to a single prompt, so a broad glob like
+	// @*.go can't blow the context budget on its own.
+	maxAttachedFiles = 20
+
+	// fallbackMaxContextTokens mirrors config.DefaultConfig's
+	// ShellSettings.MaxContextTokens, used when cfg.MaxContextTokens is unset.
+	fallbackMaxContextTokens = 1000
+
+	// bytesPerToken is the same ~4-characters-per-token rule of thumb
+	// CharTokenizer uses (see tokenizer.go), for turning a token budget into
+	// a byte budget without invoking the real tokenizer.
+	bytesPerToken = 4
+)
+
+// fileRefPattern matches @-prefixed file references embedded in a prompt,
+// e.g. "explain @main.go" or "diff @src/*.go against HEAD".
+var fileRefPattern = regexp.MustCompile(`@([^\s]+)`)
+
+// ContextAttacher attaches stdin content and @-file references to an
+// agent.Context as additional user messages, ahead of the real prompt. It's
+// the agent-mode counterpart to the shellcontext mechanism shell.BuildPrompt
+// uses for non-agent command suggestions - the two honor the same
+// config.ShellSettings fields but operate on different context types.
+type ContextAttacher struct {
+	cfg config.ShellSettings
+}
+
+// NewContextAttacher creates a ContextAttacher governed by cfg's
+// AttachStdin, AppendFileContext, and MaxContextTokens settings.
+func NewContextAttacher(cfg config.ShellSettings) *ContextAttacher {
+	return &ContextAttacher{cfg: cfg}
+}
+
+// Attach adds a stdin snapshot (if piped input is present and AttachStdin is
+// set) and the content of any @path/@glob references found in prompt (if
+// AppendFileContext is set) to ctx, each as its own user message, before the
+// caller adds the real prompt.
+func (c *ContextAttacher) Attach(ctx *Context, prompt, workingDir string) error {
+	if c.cfg.AttachStdin {
+		if err := c.attachStdin(ctx); err != nil {
+			return fmt.Errorf("failed to attach stdin: %w", err)
+		}
+	}
+	if c.cfg.AppendFileContext {
+		if err := c.attachFileReferences(ctx, prompt, workingDir); err != nil {
+			return fmt.Errorf("failed to attach @-file references: %w", err)
+		}
+	}
+	return nil
+}
+
+// attachStdin reads piped stdin, if any, up to the configured byte budget
+// and attaches it as a user message. It's a no-op when stdin is a terminal,
+// so an interactive invocation without piped input never blocks on a read.
+func (c *ContextAttacher) attachStdin(ctx *Context) error {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil
+	}
+
+	content, err := readBounded(os.Stdin, c.maxBytes())
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil
+	}
+
+	ctx.AddUserMessage(fenceContext("stdin", content))
+	return nil
+}
+
+// attachFileReferences resolves every @token in prompt to one or more files
+// under workingDir and attaches each one's content as a user message, up to
+// maxAttachedFiles total.
+func (c *ContextAttacher) attachFileReferences(ctx *Context, prompt, workingDir string) error {
+	budget := c.maxBytes()
+	attached := 0
+
+	for _, m := range fileRefPattern.FindAllStringSubmatch(prompt, -1) {
+		if attached >= maxAttachedFiles {
+			break
+		}
+
+		matches, err := resolveFileRefs(m[1], workingDir)
+		if err != nil {
+			return err
+		}
+
+		for _, path := range matches {
+			if attached >= maxAttachedFiles {
+				break
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue // best-effort: skip an unreadable match
+			}
+
+			rel, err := filepath.Rel(workingDir, path)
+			if err != nil {
+				rel = path
+			}
+
+			content := string(data)
+			if len(content) > budget {
+				content = content[:budget]
+			}
+
+			ctx.AddUserMessage(fenceContext("file:"+rel, content))
+			attached++
+		}
+	}
+
+	return nil
+}
+
+// maxBytes derives a byte budget from cfg.MaxContextTokens, falling back to
+// fallbackMaxContextTokens when unset, via the same tokens-to-bytes
+// heuristic as CharTokenizer.
+func (c *ContextAttacher) maxBytes() int {
+	tokens := c.cfg.MaxContextTokens
+	if tokens <= 0 {
+		tokens = fallbackMaxContextTokens
+	}
+	return tokens * bytesPerToken
+}
+
+// resolveFileRefs expands a single @token (the text after "@") to one or
+// more files under workingDir. A token containing glob metacharacters is
+// matched against every file under workingDir the same way
+// FileReadTool.executeGlob matches its pattern; otherwise token is treated
+// as a literal path.
+func resolveFileRefs(token, workingDir string) ([]string, error) {
+	if !strings.ContainsAny(token, "*?[") {
+		path, err := resolveAttachPath(token, workingDir)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(path); err != nil {
+			return nil, nil // best-effort: silently skip a reference to a missing file
+		}
+		return []string{path}, nil
+	}
+
+	root := workingDir
+	if root == "" {
+		root = "."
+	}
+
+	var matches []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // best-effort: skip unreadable entries
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			rel = path
+		}
+
+		matched, matchErr := filepath.Match(token, rel)
+		if matchErr != nil {
+			return matchErr
+		}
+		if !matched {
+			matched, matchErr = filepath.Match(token, filepath.Base(path))
+			if matchErr != nil {
+				return matchErr
+			}
+		}
+		if matched {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// resolveAttachPath resolves a literal @-reference path against workingDir
+// and rejects a relative path that resolves outside of it.
+func resolveAttachPath(rawPath, workingDir string) (string, error) {
+	path := rawPath
+	if !filepath.IsAbs(path) {
+		if workingDir != "" {
+			path = filepath.Join(workingDir, path)
+			// Join has already cleaned away any "..", so check where the
+			// result landed relative to workingDir instead.
+			if rel, err := filepath.Rel(workingDir, path); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return "", fmt.Errorf("path traversal not allowed: %s", rawPath)
+			}
+		} else if absPath, err := filepath.Abs(path); err == nil {
+			path = absPath
+		}
+	}
+
+	if strings.Contains(path, "..") {
+		cleanPath := filepath.Clean(path)
+		if strings.HasPrefix(cleanPath, "..") {
+			return "", fmt.Errorf("path traversal not allowed: %s", rawPath)
+		}
+	}
+
+	return path, nil
+}
+
+// fenceContext wraps content in a markdown code fence labeled with source
+// (e.g. "stdin" or "file:main.go"), so the model can tell attached context
+// apart from the user's actual question.
+func fenceContext(source, content string) string {
+	return fmt.Sprintf("```%s\n%s\n```", source, content)
+}
+
+// readBounded reads up to max bytes from r.
+func readBounded(r io.Reader, max int) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, int64(max)))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"uhh/internal/config"
+)
+
+func TestAttachFileReferencesLiteralPath(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("hello from notes"), 0644); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	ctx := NewContext("")
+	attacher := NewContextAttacher(config.ShellSettings{AppendFileContext: true})
+
+	if err := attacher.attachFileReferences(ctx, "summarize @notes.txt please", dir); err != nil {
+		t.Fatalf("attachFileReferences: %v", err)
+	}
+
+	msg := ctx.Nodes[ctx.Head]
+	if msg == nil || !strings.Contains(msg.Content, "hello from notes") {
+		t.Fatalf("expected attached file content in latest message, got %+v", msg)
+	}
+	if !strings.Contains(msg.Content, "file:notes.txt") {
+		t.Fatalf("expected fence labeled with source path, got %q", msg.Content)
+	}
+}
+
+func TestAttachFileReferencesGlobCapsAtMaxAttachedFiles(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < maxAttachedFiles+5; i++ {
+		name := filepath.Join(dir, "f"+string(rune('a'+i))+".txt")
+		if err := os.WriteFile(name, []byte("x"), 0644); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+
+	ctx := NewContext("")
+	attacher := NewContextAttacher(config.ShellSettings{AppendFileContext: true})
+
+	if err := attacher.attachFileReferences(ctx, "review @*.txt", dir); err != nil {
+		t.Fatalf("attachFileReferences: %v", err)
+	}
+
+	attached := 0
+	for n := ctx.Head; n != ""; {
+		msg := ctx.Nodes[n]
+		if msg == nil {
+			break
+		}
+		if strings.HasPrefix(msg.Content, "```file:") {
+			attached++
+		}
+		n = msg.ParentID
+	}
+	if attached != maxAttachedFiles {
+		t.Fatalf("attached %d files, want cap of %d", attached, maxAttachedFiles)
+	}
+}
+
+func TestResolveAttachPathRejectsTraversal(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := resolveAttachPath("../../etc/passwd", dir); err == nil {
+		t.Fatalf("expected traversal outside workingDir to be rejected")
+	}
+
+	got, err := resolveAttachPath("notes.txt", dir)
+	if err != nil {
+		t.Fatalf("resolveAttachPath: %v", err)
+	}
+	if got != filepath.Join(dir, "notes.txt") {
+		t.Fatalf("resolveAttachPath = %q, want %q", got, filepath.Join(dir, "notes.txt"))
+	}
+}
+
+func TestAttachFileReferencesSkipsMissingReference(t *testing.T) {
+	dir := t.TempDir()
+
+	ctx := NewContext("")
+	attacher := NewContextAttacher(config.ShellSettings{AppendFileContext: true})
+
+	if err := attacher.attachFileReferences(ctx, "explain @does-not-exist.txt", dir); err != nil {
+		t.Fatalf("attachFileReferences: %v", err)
+	}
+	if ctx.Head != "" {
+		t.Fatalf("expected no message attached for a missing file reference")
+	}
+}
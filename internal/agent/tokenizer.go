@@ -0,0 +1,72 @@
+package agent
+
+import (
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// Tokenizer estimates how many tokens a piece of text costs, so Context can
+// budget conversation history by token count rather than raw message count
+// - a much better fit for tool-heavy turns where a single bash/file_read
+// result can dwarf a dozen short chat messages.
+type Tokenizer interface {
+	CountTokens(text string) int
+}
+
+// CharTokenizer is a crude fallback that assumes ~4 characters per token,
+// the commonly cited rule of thumb for English text. Used whenever a real
+// BPE encoding can't be loaded.
+type CharTokenizer struct{}
+
+// CountTokens implements Tokenizer.
+func (CharTokenizer) CountTokens(text string) int {
+	if text == "" {
+		return 0
+	}
+	n := len(text) / 4
+	if n == 0 {
+		n = 1
+	}
+	return n
+}
+
+// TiktokenTokenizer wraps a tiktoken-go BPE encoding for accurate
+// OpenAI-style token counts.
+type TiktokenTokenizer struct {
+	enc *tiktoken.Tiktoken
+}
+
+// NewTiktokenTokenizer loads the BPE encoding for model, falling back to
+// cl100k_base (the encoding shared by gpt-3.5-turbo/gpt-4) if model isn't
+// recognized.
+func NewTiktokenTokenizer(model string) (*TiktokenTokenizer, error) {
+	enc, err := tiktoken.EncodingForModel(model)
+	if err != nil {
+		enc, err = tiktoken.GetEncoding("cl100k_base")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &TiktokenTokenizer{enc: enc}, nil
+}
+
+// CountTokens implements Tokenizer.
+func (t *TiktokenTokenizer) CountTokens(text string) int {
+	return len(t.enc.Encode(text, nil, nil))
+}
+
+// DefaultTokenizer returns a tokenizer appropriate for providerName: a
+// tiktoken BPE encoder for OpenAI and OpenAI-compatible providers (DeepSeek,
+// GLM, local, Ollama - close enough in practice), falling back to
+// CharTokenizer when no encoding can be loaded or the provider's
+// tokenization scheme isn't BPE-compatible (e.g. Anthropic, Gemini).
+func DefaultTokenizer(providerName string) Tokenizer {
+	switch providerName {
+	case "anthropic", "gemini":
+		return CharTokenizer{}
+	}
+
+	if tok, err := NewTiktokenTokenizer("gpt-4o"); err == nil {
+		return tok
+	}
+	return CharTokenizer{}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -23,6 +24,79 @@ type Config struct {
 
 	// UI settings
 	UI UISettings `json:"ui"`
+
+	// Profiles contains named overlays switchable per invocation (e.g. "work", "personal")
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+
+	// DefaultProfile is the profile applied when none is selected via --profile/-P or UHH_PROFILE
+	DefaultProfile string `json:"default_profile,omitempty"`
+
+	// MCPServers declares external Model Context Protocol servers whose tools
+	// should be registered into the agent's tool registry at startup.
+	MCPServers []MCPServerConfig `json:"mcp_servers,omitempty"`
+
+	// GalleryURL overrides where the model gallery index (see package
+	// gallery) is fetched from. Empty uses the built-in default.
+	GalleryURL string `json:"gallery_url,omitempty"`
+
+	// Agents declares named agent profiles - e.g. a "coder" agent with
+	// filesystem/exec tools and a coding system prompt vs. a "shell" agent
+	// restricted to shell-helper tools - selectable via --agent-profile.
+	// See AgentProfile and agent.Profile.
+	Agents map[string]AgentProfile `json:"agents,omitempty"`
+
+	// Update controls self-update verification strictness.
+	Update UpdateSettings `json:"update"`
+
+	// Pricing gives per-model $/1k-token input/output costs, used to report
+	// each agent run's estimated cost and to populate `uhh usage`'s totals.
+	// A model with no entry here just reports $0 cost.
+	Pricing map[string]ModelPricing `json:"pricing,omitempty"`
+
+	// SystemPromptTemplate and StopWords are populated by WithProfile from
+	// the applied profile (see ~/.uhh/models/*.yaml via loadModelProfiles).
+	// They're never read from config.json directly on the base Config.
+	SystemPromptTemplate string   `json:"-"`
+	StopWords            []string `json:"-"`
+}
+
+// ModelPricing is the $ cost per 1,000 tokens for a single model's input
+// and output tokens. It mirrors provider.ModelPricing; the cmd layer
+// translates Config.Pricing into a provider.PricingTable at startup rather
+// than importing internal/provider from here.
+type ModelPricing struct {
+	InputPer1K  float64 `json:"input_per_1k"`
+	OutputPer1K float64 `json:"output_per_1k"`
+}
+
+// MCPServerConfig describes a single MCP server to connect to: either a
+// stdio subprocess (Command/Args/Env) or an HTTP/SSE endpoint (URL).
+type MCPServerConfig struct {
+	Name    string            `json:"name"`
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Trusted bool              `json:"trusted,omitempty"`
+}
+
+// Profile is a named overlay of provider/agent/shell settings, applied on
+// top of the base config for the duration of a single invocation. Only
+// non-zero fields override the base; omit a field to inherit it.
+type Profile struct {
+	DefaultProvider string                      `json:"default_provider,omitempty"`
+	Providers       map[string]ProviderSettings `json:"providers,omitempty"`
+	Agent           *AgentSettings              `json:"agent,omitempty"`
+	Shell           *ShellSettings              `json:"shell,omitempty"`
+
+	// SystemPromptTemplate is a Go text/template string rendered with
+	// Shell/CWD/OS/FileContext placeholders (see shell.RenderSystemPromptTemplate)
+	// and used as the agent's system prompt in place of the default, when set.
+	SystemPromptTemplate string `json:"system_prompt_template,omitempty"`
+
+	// StopWords are passed to the provider as stop sequences while this
+	// profile is active.
+	StopWords []string `json:"stop_words,omitempty"`
 }
 
 // ProviderSettings contains settings for a single provider
@@ -33,6 +107,16 @@ type ProviderSettings struct {
 	BaseURL     string  `json:"base_url,omitempty"`
 	Temperature float64 `json:"temperature,omitempty"`
 	MaxTokens   int     `json:"max_tokens,omitempty"`
+
+	// Command, Socket, and Env describe a plugin-backed provider spawned as a
+	// subprocess exposing a gRPC server over a Unix socket instead of being
+	// dialed at BaseURL (see provider.RegisterCommand). Command and Args name the
+	// executable; Socket overrides the default derived socket path; Env adds
+	// extra environment variables for the subprocess.
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Socket  string            `json:"socket,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
 }
 
 // AgentSettings contains agent-specific configuration
@@ -42,11 +126,58 @@ type AgentSettings struct {
 	EnabledTools  []string `json:"enabled_tools"`
 }
 
+// AgentProfile is a named agent definition: its system prompt, the subset
+// of registered tools it may call, and optional default
+// provider/model/temperature overrides. Unlike Profile (a per-invocation
+// overlay of the whole config), an AgentProfile describes one persona an
+// agent-mode invocation can opt into via --agent-profile, e.g. a "coder"
+// agent with filesystem/exec tools vs. a "shell" agent restricted to
+// shell-helper tools.
+type AgentProfile struct {
+	// SystemPrompt replaces the default agent system prompt when set.
+	SystemPrompt string `json:"system_prompt,omitempty"`
+
+	// Tools allow-lists the registered tool names this agent may call. Empty
+	// means every tool in the registry is available, same as today.
+	Tools []string `json:"tools,omitempty"`
+
+	// Provider, Model, and Temperature override the active provider's
+	// defaults for this agent when set.
+	Provider    string  `json:"provider,omitempty"`
+	Model       string  `json:"model,omitempty"`
+	Temperature float64 `json:"temperature,omitempty"`
+}
+
 // ShellSettings contains shell-related configuration
 type ShellSettings struct {
 	Override          string `json:"override,omitempty"`
 	AppendFileContext bool   `json:"append_file_context"`
 	MaxContextTokens  int    `json:"max_context_tokens"`
+
+	// Sandbox selects the isolation mode for the bash and file_write tools:
+	// "" or "host" (default, current behavior), "docker", "podman", or
+	// "firejail" (Linux only). See internal/tools.NewSandbox.
+	Sandbox string `json:"sandbox,omitempty"`
+
+	// SandboxImage is the container image used by the docker/podman
+	// sandboxes. Ignored by host and firejail. Defaults to a small image
+	// with a POSIX shell if unset.
+	SandboxImage string `json:"sandbox_image,omitempty"`
+
+	// SandboxReadOnly bind-mounts the working directory read-only instead
+	// of read-write, for commands that should never be able to write back.
+	SandboxReadOnly bool `json:"sandbox_read_only,omitempty"`
+
+	// SandboxNetwork is passed through to the container runtime's
+	// --network flag (e.g. "none", "bridge"). Defaults to "none" when a
+	// container sandbox is active and this is unset.
+	SandboxNetwork string `json:"sandbox_network,omitempty"`
+
+	// AttachStdin attaches piped stdin content to the agent's context as an
+	// additional user message before the real prompt, when uhh is invoked
+	// with data piped in. See agent.ContextAttacher. Ignored when stdin is a
+	// terminal.
+	AttachStdin bool `json:"attach_stdin,omitempty"`
 }
 
 // UISettings contains UI preferences
@@ -56,6 +187,22 @@ type UISettings struct {
 	ShowSpinner bool   `json:"show_spinner"`
 }
 
+// UpdateSettings controls self-update verification (see updater.PerformUpdate).
+type UpdateSettings struct {
+	// RequireSignature rejects an update unless its checksums.txt is signed
+	// and the signature verifies against the embedded release public key.
+	RequireSignature bool `json:"require_signature"`
+
+	// Channel is the default release channel `uhh update` checks
+	// (updater.ChannelStable, ChannelBeta, or ChannelNightly); overridden
+	// per-invocation by the --channel flag.
+	Channel string `json:"channel,omitempty"`
+
+	// MaxBackups caps how many previous binaries are kept for `uhh update
+	// rollback`; 0 falls back to the updater package's default of 3.
+	MaxBackups int `json:"max_backups,omitempty"`
+}
+
 // ConfigDir returns the path to the config directory
 func ConfigDir() (string, error) {
 	home, err := os.UserHomeDir()
@@ -94,6 +241,17 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	// Merge ~/.uhh/models/*.yaml model profiles in after JSON parsing but
+	// before env overrides, so UHH_PROFILE/--profile can still select one.
+	if modelProfiles := loadModelProfiles(); len(modelProfiles) > 0 {
+		if cfg.Profiles == nil {
+			cfg.Profiles = make(map[string]Profile)
+		}
+		for name, profile := range modelProfiles {
+			cfg.Profiles[name] = profile
+		}
+	}
+
 	// Apply environment variable overrides
 	cfg.applyEnvOverrides()
 
@@ -139,11 +297,12 @@ func (c *Config) applyEnvOverrides() {
 
 	// API key overrides
 	envKeys := map[string]string{
-		"openai":   "OPENAI_API_KEY",
-		"gemini":   "GOOGLE_API_KEY",
-		"deepseek": "DEEPSEEK_API_KEY",
-		"kimi":     "MOONSHOT_API_KEY",
-		"glm":      "GLM_API_KEY",
+		"openai":    "OPENAI_API_KEY",
+		"gemini":    "GOOGLE_API_KEY",
+		"deepseek":  "DEEPSEEK_API_KEY",
+		"kimi":      "MOONSHOT_API_KEY",
+		"glm":       "GLM_API_KEY",
+		"anthropic": "ANTHROPIC_API_KEY",
 	}
 
 	for provider, envVar := range envKeys {
@@ -178,6 +337,11 @@ func (c *Config) applyEnvOverrides() {
 		c.Agent.AutoApprove = autoApprove == "1" || autoApprove == "true"
 	}
 
+	// Gallery index URL override
+	if galleryURL := os.Getenv("UHH_GALLERY_URL"); galleryURL != "" {
+		c.GalleryURL = galleryURL
+	}
+
 	// File context override
 	if appendContext := os.Getenv("UHH_APPEND_SMALL_CONTEXT"); appendContext != "" {
 		if appendContext == "true" || appendContext == "1" {
@@ -202,3 +366,147 @@ func (c *Config) GetActiveProvider() string {
 	}
 	return c.DefaultProvider
 }
+
+// GetActiveProfile returns the name of the profile that should be applied,
+// preferring the UHH_PROFILE environment variable over the configured default.
+func (c *Config) GetActiveProfile() string {
+	if profile := os.Getenv("UHH_PROFILE"); profile != "" {
+		return profile
+	}
+	return c.DefaultProfile
+}
+
+// WithProfile returns a copy of c with the named profile overlaid on top.
+// An empty name returns c unchanged; an unknown profile returns an error.
+func (c *Config) WithProfile(name string) (*Config, error) {
+	if name == "" {
+		return c, nil
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile: %s", name)
+	}
+
+	// Shallow-copy the base config, then deep-copy the maps we mutate.
+	effective := *c
+	effective.Providers = make(map[string]ProviderSettings, len(c.Providers))
+	for k, v := range c.Providers {
+		effective.Providers[k] = v
+	}
+
+	if profile.DefaultProvider != "" {
+		effective.DefaultProvider = profile.DefaultProvider
+	}
+	for name, override := range profile.Providers {
+		effective.Providers[name] = mergeProviderSettings(effective.Providers[name], override)
+	}
+	if profile.Agent != nil {
+		effective.Agent = mergeAgentSettings(effective.Agent, *profile.Agent)
+	}
+	if profile.Shell != nil {
+		effective.Shell = mergeShellSettings(effective.Shell, *profile.Shell)
+	}
+	effective.SystemPromptTemplate = profile.SystemPromptTemplate
+	effective.StopWords = profile.StopWords
+
+	return &effective, nil
+}
+
+// mergeProviderSettings overlays override onto base field-by-field, so a
+// profile only needs to declare the fields it actually changes (e.g. a
+// model-profile YAML file that sets Model and Temperature doesn't wipe out
+// the base config's APIKey).
+func mergeProviderSettings(base, override ProviderSettings) ProviderSettings {
+	merged := base
+	if override.Enabled {
+		merged.Enabled = true
+	}
+	if override.APIKey != "" {
+		merged.APIKey = override.APIKey
+	}
+	if override.Model != "" {
+		merged.Model = override.Model
+	}
+	if override.BaseURL != "" {
+		merged.BaseURL = override.BaseURL
+	}
+	if override.Temperature != 0 {
+		merged.Temperature = override.Temperature
+	}
+	if override.MaxTokens != 0 {
+		merged.MaxTokens = override.MaxTokens
+	}
+	if override.Command != "" {
+		merged.Command = override.Command
+	}
+	if len(override.Args) > 0 {
+		merged.Args = override.Args
+	}
+	if override.Socket != "" {
+		merged.Socket = override.Socket
+	}
+	if len(override.Env) > 0 {
+		merged.Env = override.Env
+	}
+	return merged
+}
+
+// mergeAgentSettings overlays override onto base field-by-field, so a
+// profile that only wants to restrict EnabledTools doesn't also reset
+// AutoApprove/MaxIterations to their zero values.
+func mergeAgentSettings(base, override AgentSettings) AgentSettings {
+	merged := base
+	if override.AutoApprove {
+		merged.AutoApprove = true
+	}
+	if override.MaxIterations != 0 {
+		merged.MaxIterations = override.MaxIterations
+	}
+	if len(override.EnabledTools) > 0 {
+		merged.EnabledTools = override.EnabledTools
+	}
+	return merged
+}
+
+// mergeShellSettings overlays override onto base field-by-field, for the
+// same reason as mergeAgentSettings.
+func mergeShellSettings(base, override ShellSettings) ShellSettings {
+	merged := base
+	if override.Override != "" {
+		merged.Override = override.Override
+	}
+	if override.AppendFileContext {
+		merged.AppendFileContext = true
+	}
+	if override.MaxContextTokens != 0 {
+		merged.MaxContextTokens = override.MaxContextTokens
+	}
+	if override.Sandbox != "" {
+		merged.Sandbox = override.Sandbox
+	}
+	if override.SandboxImage != "" {
+		merged.SandboxImage = override.SandboxImage
+	}
+	if override.SandboxReadOnly {
+		merged.SandboxReadOnly = true
+	}
+	if override.SandboxNetwork != "" {
+		merged.SandboxNetwork = override.SandboxNetwork
+	}
+	if override.AttachStdin {
+		merged.AttachStdin = true
+	}
+	return merged
+}
+
+// GetProviderSettingsForProfile returns provider's settings with the named
+// profile's overrides applied, without the caller needing to build a whole
+// effective Config via WithProfile first.
+func (c *Config) GetProviderSettingsForProfile(provider, profileName string) (ProviderSettings, bool) {
+	effective, err := c.WithProfile(profileName)
+	if err != nil {
+		return c.GetProviderSettings(provider)
+	}
+	return effective.GetProviderSettings(provider)
+}
@@ -2,20 +2,25 @@ package config
 
 // Provider type constants
 const (
-	ProviderOpenAI   = "openai"
-	ProviderGemini   = "gemini"
-	ProviderDeepseek = "deepseek"
-	ProviderKimi     = "kimi"
-	ProviderGLM      = "glm"
+	ProviderOpenAI    = "openai"
+	ProviderGemini    = "gemini"
+	ProviderDeepseek  = "deepseek"
+	ProviderKimi      = "kimi"
+	ProviderGLM       = "glm"
+	ProviderLocal     = "local"
+	ProviderAnthropic = "anthropic"
+	ProviderOllama    = "ollama"
 )
 
 // Default model names for each provider
 var DefaultModels = map[string]string{
-	ProviderOpenAI:   "gpt-4o",
-	ProviderGemini:   "gemini-2.0-flash",
-	ProviderDeepseek: "deepseek-chat",
-	ProviderKimi:     "kimi-coding/k2p5",
-	ProviderGLM:      "glm-4",
+	ProviderOpenAI:    "gpt-4o",
+	ProviderGemini:    "gemini-2.0-flash",
+	ProviderDeepseek:  "deepseek-chat",
+	ProviderKimi:      "kimi-coding/k2p5",
+	ProviderGLM:       "glm-4",
+	ProviderAnthropic: "claude-3-5-sonnet-20241022",
+	ProviderOllama:    "llama3.1",
 }
 
 // Default base URLs for OpenAI-compatible providers
@@ -23,15 +28,19 @@ var DefaultBaseURLs = map[string]string{
 	ProviderDeepseek: "https://api.deepseek.com/v1",
 	ProviderKimi:     "https://api.moonshot.cn/v1",
 	ProviderGLM:      "https://open.bigmodel.cn/api/paas/v4",
+	ProviderOllama:   "http://localhost:11434",
 }
 
 // Provider display names
 var ProviderDisplayNames = map[string]string{
-	ProviderOpenAI:   "OpenAI",
-	ProviderGemini:   "Google Gemini",
-	ProviderDeepseek: "DeepSeek",
-	ProviderKimi:     "Kimi (Moonshot)",
-	ProviderGLM:      "GLM (Zhipu AI)",
+	ProviderOpenAI:    "OpenAI",
+	ProviderGemini:    "Google Gemini",
+	ProviderDeepseek:  "DeepSeek",
+	ProviderKimi:      "Kimi (Moonshot)",
+	ProviderGLM:       "GLM (Zhipu AI)",
+	ProviderLocal:     "Local / Self-hosted",
+	ProviderAnthropic: "Anthropic",
+	ProviderOllama:    "Ollama",
 }
 
 // DefaultConfig returns a config with sensible defaults
@@ -67,6 +76,21 @@ func DefaultConfig() *Config {
 				BaseURL:     DefaultBaseURLs[ProviderGLM],
 				Temperature: 0.7,
 			},
+			ProviderLocal: {
+				Enabled:     false,
+				Temperature: 0.7,
+			},
+			ProviderAnthropic: {
+				Enabled:     false,
+				Model:       DefaultModels[ProviderAnthropic],
+				Temperature: 0.7,
+			},
+			ProviderOllama: {
+				Enabled:     false,
+				Model:       DefaultModels[ProviderOllama],
+				BaseURL:     DefaultBaseURLs[ProviderOllama],
+				Temperature: 0.7,
+			},
 		},
 		Agent: AgentSettings{
 			AutoApprove:   false,
@@ -83,6 +107,11 @@ func DefaultConfig() *Config {
 			Theme:       "charm",
 			ShowSpinner: true,
 		},
+		Update: UpdateSettings{
+			RequireSignature: false,
+			Channel:          "stable",
+			MaxBackups:       3,
+		},
 	}
 }
 
@@ -94,5 +123,8 @@ func AllProviders() []string {
 		ProviderDeepseek,
 		ProviderKimi,
 		ProviderGLM,
+		ProviderLocal,
+		ProviderAnthropic,
+		ProviderOllama,
 	}
 }
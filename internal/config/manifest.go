@@ -0,0 +1,175 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest is a declarative description of the desired configuration,
+// loaded from a committed uhh.yaml so teams can provision machines
+// non-interactively instead of running the interactive init wizard.
+type Manifest struct {
+	DefaultProvider string                      `yaml:"default_provider"`
+	Providers       map[string]ManifestProvider `yaml:"providers"`
+	EnabledTools    []string                    `yaml:"enabled_tools"`
+	AutoApprove     bool                        `yaml:"auto_approve"`
+	MaxIterations   int                         `yaml:"max_iterations"`
+}
+
+// ManifestProvider describes the desired state of a single provider entry.
+// APIKey may reference an environment variable as ${ENV_VAR}, resolved at
+// load time so the manifest itself can be committed to a repo.
+type ManifestProvider struct {
+	Enabled     bool    `yaml:"enabled"`
+	APIKey      string  `yaml:"api_key"`
+	Model       string  `yaml:"model"`
+	BaseURL     string  `yaml:"base_url"`
+	Temperature float64 `yaml:"temperature"`
+}
+
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// LoadManifest reads and parses a manifest file, resolving ${ENV_VAR}
+// references in API keys.
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	for name, p := range m.Providers {
+		p.APIKey = resolveEnvRefs(p.APIKey)
+		m.Providers[name] = p
+	}
+
+	return &m, nil
+}
+
+// resolveEnvRefs replaces ${ENV_VAR} occurrences with the corresponding
+// environment variable value, leaving unresolved references untouched.
+func resolveEnvRefs(s string) string {
+	return envRefPattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := envRefPattern.FindStringSubmatch(match)[1]
+		if val, ok := os.LookupEnv(name); ok {
+			return val
+		}
+		return match
+	})
+}
+
+// Diff reports the changes applying the manifest would make to cfg, one
+// line per change, without mutating cfg.
+func (m *Manifest) Diff(cfg *Config) []string {
+	var changes []string
+
+	if m.DefaultProvider != "" && m.DefaultProvider != cfg.DefaultProvider {
+		changes = append(changes, fmt.Sprintf("default_provider: %q -> %q", cfg.DefaultProvider, m.DefaultProvider))
+	}
+
+	if len(m.EnabledTools) > 0 && !stringSlicesEqual(m.EnabledTools, cfg.Agent.EnabledTools) {
+		changes = append(changes, fmt.Sprintf("agent.enabled_tools: %v -> %v", cfg.Agent.EnabledTools, m.EnabledTools))
+	}
+
+	if m.AutoApprove != cfg.Agent.AutoApprove {
+		changes = append(changes, fmt.Sprintf("agent.auto_approve: %v -> %v", cfg.Agent.AutoApprove, m.AutoApprove))
+	}
+
+	if m.MaxIterations > 0 && m.MaxIterations != cfg.Agent.MaxIterations {
+		changes = append(changes, fmt.Sprintf("agent.max_iterations: %d -> %d", cfg.Agent.MaxIterations, m.MaxIterations))
+	}
+
+	for name, desired := range m.Providers {
+		current, exists := cfg.Providers[name]
+		if !exists {
+			changes = append(changes, fmt.Sprintf("providers.%s: create (enabled=%v, model=%s)", name, desired.Enabled, desired.Model))
+			continue
+		}
+		if desired.Enabled != current.Enabled {
+			changes = append(changes, fmt.Sprintf("providers.%s.enabled: %v -> %v", name, current.Enabled, desired.Enabled))
+		}
+		if desired.Model != "" && desired.Model != current.Model {
+			changes = append(changes, fmt.Sprintf("providers.%s.model: %q -> %q", name, current.Model, desired.Model))
+		}
+		if desired.BaseURL != "" && desired.BaseURL != current.BaseURL {
+			changes = append(changes, fmt.Sprintf("providers.%s.base_url: %q -> %q", name, current.BaseURL, desired.BaseURL))
+		}
+		if desired.APIKey != "" && desired.APIKey != current.APIKey {
+			changes = append(changes, fmt.Sprintf("providers.%s.api_key: (changed)", name))
+		}
+		if desired.Temperature != 0 && desired.Temperature != current.Temperature {
+			changes = append(changes, fmt.Sprintf("providers.%s.temperature: %v -> %v", name, current.Temperature, desired.Temperature))
+		}
+	}
+
+	// Disable providers that exist on disk but aren't declared in the manifest.
+	for name, current := range cfg.Providers {
+		if _, declared := m.Providers[name]; !declared && current.Enabled {
+			changes = append(changes, fmt.Sprintf("providers.%s.enabled: %v -> false (not in manifest)", name, current.Enabled))
+		}
+	}
+
+	return changes
+}
+
+// Apply reconciles cfg in place to match the manifest.
+func (m *Manifest) Apply(cfg *Config) {
+	if m.DefaultProvider != "" {
+		cfg.DefaultProvider = m.DefaultProvider
+	}
+	if len(m.EnabledTools) > 0 {
+		cfg.Agent.EnabledTools = m.EnabledTools
+	}
+	cfg.Agent.AutoApprove = m.AutoApprove
+	if m.MaxIterations > 0 {
+		cfg.Agent.MaxIterations = m.MaxIterations
+	}
+
+	if cfg.Providers == nil {
+		cfg.Providers = make(map[string]ProviderSettings)
+	}
+
+	for name, desired := range m.Providers {
+		settings := cfg.Providers[name]
+		settings.Enabled = desired.Enabled
+		if desired.Model != "" {
+			settings.Model = desired.Model
+		}
+		if desired.BaseURL != "" {
+			settings.BaseURL = desired.BaseURL
+		}
+		if desired.APIKey != "" {
+			settings.APIKey = desired.APIKey
+		}
+		if desired.Temperature != 0 {
+			settings.Temperature = desired.Temperature
+		}
+		cfg.Providers[name] = settings
+	}
+
+	for name, current := range cfg.Providers {
+		if _, declared := m.Providers[name]; !declared {
+			current.Enabled = false
+			cfg.Providers[name] = current
+		}
+	}
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
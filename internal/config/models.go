@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ModelsDir returns ~/.uhh/models, scanned by loadModelProfiles for
+// per-model YAML presets. This mirrors LocalAI's per-model YAML config
+// approach: one file per reusable, shareable preset instead of editing the
+// single global config.json.
+func ModelsDir() string {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "./.uhh/models"
+	}
+	return filepath.Join(dir, "models")
+}
+
+// modelProfileFile is the YAML shape of a single ~/.uhh/models/*.yaml file.
+// Name defaults to the filename (without extension) when omitted.
+type modelProfileFile struct {
+	Name                 string   `yaml:"name"`
+	Provider             string   `yaml:"provider"`
+	Model                string   `yaml:"model"`
+	Temperature          float64  `yaml:"temperature"`
+	MaxTokens            int      `yaml:"max_tokens"`
+	SystemPromptTemplate string   `yaml:"system_prompt_template"`
+	StopWords            []string `yaml:"stop_words"`
+	EnabledTools         []string `yaml:"enabled_tools"`
+}
+
+// loadModelProfiles scans ModelsDir() for *.yaml/*.yml files and converts
+// each into a Profile keyed by its name, selectable the same way as any
+// config.json profile via --profile/-P or UHH_PROFILE. A missing directory
+// or an individual malformed file is skipped rather than failing Load.
+func loadModelProfiles() map[string]Profile {
+	dir := ModelsDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	profiles := make(map[string]Profile)
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(entry.Name())
+		if ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+
+		var raw modelProfileFile
+		if err := yaml.Unmarshal(data, &raw); err != nil || raw.Provider == "" {
+			continue
+		}
+
+		name := raw.Name
+		if name == "" {
+			name = strings.TrimSuffix(entry.Name(), ext)
+		}
+
+		profile := Profile{
+			DefaultProvider: raw.Provider,
+			Providers: map[string]ProviderSettings{
+				raw.Provider: {
+					Model:       raw.Model,
+					Temperature: raw.Temperature,
+					MaxTokens:   raw.MaxTokens,
+				},
+			},
+			SystemPromptTemplate: raw.SystemPromptTemplate,
+			StopWords:            raw.StopWords,
+		}
+		if len(raw.EnabledTools) > 0 {
+			profile.Agent = &AgentSettings{EnabledTools: raw.EnabledTools}
+		}
+
+		profiles[name] = profile
+	}
+
+	return profiles
+}
@@ -0,0 +1,168 @@
+// Package filebackup snapshots file contents before FileWriteTool
+// overwrites them, so the `uhh undo` subcommand can restore a previous
+// version.
+package filebackup
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"uhh/internal/config"
+)
+
+// Record describes one snapshot of a file taken before it was overwritten.
+type Record struct {
+	OriginalPath string    `json:"original_path"`
+	BackupPath   string    `json:"backup_path"`
+	SHA256       string    `json:"sha256"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// Dir returns the directory backed-up file contents and the index are
+// stored under, creating it if necessary.
+func Dir() (string, error) {
+	configDir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(configDir, "backups", "files")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func indexPath(dir string) string {
+	return filepath.Join(dir, "index.jsonl")
+}
+
+// Save snapshots path's current contents into
+// ~/.uhh/backups/files/<sha256>/<basename>.<timestamp>, appends a record to
+// the index, and returns it. If path doesn't exist yet - a fresh create,
+// nothing to back up - Save is a no-op returning a zero Record.
+func Save(path string) (Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Record{}, nil
+		}
+		return Record{}, err
+	}
+
+	dir, err := Dir()
+	if err != nil {
+		return Record{}, err
+	}
+
+	sum := sha256.Sum256(data)
+	sumHex := hex.EncodeToString(sum[:])
+
+	destDir := filepath.Join(dir, sumHex)
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return Record{}, err
+	}
+
+	ts := time.Now().UTC()
+	destPath := filepath.Join(destDir, fmt.Sprintf("%s.%s", filepath.Base(path), ts.Format("20060102T150405.000000000")))
+	if err := os.WriteFile(destPath, data, 0644); err != nil {
+		return Record{}, err
+	}
+
+	rec := Record{
+		OriginalPath: path,
+		BackupPath:   destPath,
+		SHA256:       sumHex,
+		Timestamp:    ts,
+	}
+	if err := appendIndex(dir, rec); err != nil {
+		return Record{}, err
+	}
+	return rec, nil
+}
+
+func appendIndex(dir string, rec Record) error {
+	f, err := os.OpenFile(indexPath(dir), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// List returns every recorded backup, most recently taken first.
+func List() ([]Record, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(indexPath(dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Timestamp.After(records[j].Timestamp)
+	})
+	return records, nil
+}
+
+// Latest returns the most recent backup taken for path, or ok=false if none
+// exists.
+func Latest(path string) (Record, bool, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+
+	records, err := List()
+	if err != nil {
+		return Record{}, false, err
+	}
+	for _, rec := range records {
+		if rec.OriginalPath == path || rec.OriginalPath == abs {
+			return rec, true, nil
+		}
+	}
+	return Record{}, false, nil
+}
+
+// Restore copies rec's backed-up content back over rec.OriginalPath.
+func Restore(rec Record) error {
+	data, err := os.ReadFile(rec.BackupPath)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(rec.OriginalPath, data, 0644)
+}
@@ -0,0 +1,197 @@
+// Package gallery implements a curated, remotely hosted index of
+// recommended models (similar to LocalAI's model gallery), turning model
+// discovery from "know the exact ID" into "uhh gallery install <name>".
+package gallery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"uhh/internal/config"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultIndexURL is used when Config.GalleryURL (or UHH_GALLERY_URL) isn't set.
+const defaultIndexURL = "https://raw.githubusercontent.com/nokusukun/uhh/main/gallery/uhh-gallery.yaml"
+
+// Entry describes one recommended model listed in the gallery index.
+type Entry struct {
+	ID             string   `json:"id" yaml:"id"`
+	Provider       string   `json:"provider" yaml:"provider"`
+	ContextWindow  int      `json:"context_window,omitempty" yaml:"context_window,omitempty"`
+	RecommendedFor []string `json:"recommended_for,omitempty" yaml:"recommended_for,omitempty"`
+	SystemPrompt   string   `json:"system_prompt,omitempty" yaml:"system_prompt,omitempty"`
+	ToolDefaults   []string `json:"tool_defaults,omitempty" yaml:"tool_defaults,omitempty"`
+	BaseURL        string   `json:"base_url,omitempty" yaml:"base_url,omitempty"`
+}
+
+// index is the top-level shape of a gallery index document.
+type index struct {
+	Models []Entry `json:"models" yaml:"models"`
+}
+
+// CacheDir returns ~/.uhh/cache, where the fetched index and its ETag are cached.
+func CacheDir() string {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "./.uhh/cache"
+	}
+	return filepath.Join(dir, "cache")
+}
+
+func cachePath() string { return filepath.Join(CacheDir(), "gallery.json") }
+func etagPath() string  { return filepath.Join(CacheDir(), "gallery.etag") }
+
+// List fetches the gallery index for cfg's GalleryURL (falling back to the
+// cached copy if the fetch fails) and returns its entries.
+func List(ctx context.Context, cfg *config.Config) ([]Entry, error) {
+	url := cfg.GalleryURL
+	if url == "" {
+		url = defaultIndexURL
+	}
+
+	idx, err := loadIndex(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return idx.Models, nil
+}
+
+// Get returns the gallery entry with the given id.
+func Get(ctx context.Context, cfg *config.Config, id string) (*Entry, error) {
+	entries, err := List(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.ID == id {
+			return &e, nil
+		}
+	}
+	return nil, fmt.Errorf("gallery: no model named %q", id)
+}
+
+// Install writes a profile named after entry.ID into cfg (selectable the
+// same way as any config.json or ~/.uhh/models/*.yaml profile), creating or
+// updating entry.Provider's ProviderSettings so the model can actually be
+// reached. apiKey is applied to the provider settings if non-empty; it's the
+// caller's job to prompt for one (e.g. via tui) when the provider isn't
+// already configured. Callers must call cfg.Save() afterward.
+func Install(cfg *config.Config, entry Entry, apiKey string, setDefault bool) {
+	if cfg.Providers == nil {
+		cfg.Providers = make(map[string]config.ProviderSettings)
+	}
+
+	settings := cfg.Providers[entry.Provider]
+	settings.Enabled = true
+	settings.Model = entry.ID
+	if entry.BaseURL != "" {
+		settings.BaseURL = entry.BaseURL
+	}
+	if apiKey != "" {
+		settings.APIKey = apiKey
+	}
+	cfg.Providers[entry.Provider] = settings
+
+	profile := config.Profile{
+		DefaultProvider: entry.Provider,
+		Providers: map[string]config.ProviderSettings{
+			entry.Provider: {Model: entry.ID},
+		},
+		SystemPromptTemplate: entry.SystemPrompt,
+	}
+	if len(entry.ToolDefaults) > 0 {
+		profile.Agent = &config.AgentSettings{EnabledTools: entry.ToolDefaults}
+	}
+
+	if cfg.Profiles == nil {
+		cfg.Profiles = make(map[string]config.Profile)
+	}
+	cfg.Profiles[entry.ID] = profile
+
+	if setDefault {
+		cfg.DefaultProvider = entry.Provider
+		cfg.DefaultProfile = entry.ID
+	}
+}
+
+// loadIndex fetches url, sending the cached ETag (if any) as If-None-Match.
+// A 304 or a request failure falls back to the cached copy under CacheDir().
+func loadIndex(ctx context.Context, url string) (index, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return index{}, err
+	}
+	if etag, err := os.ReadFile(etagPath()); err == nil {
+		req.Header.Set("If-None-Match", strings.TrimSpace(string(etag)))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if cached, cerr := loadCachedIndex(); cerr == nil {
+			return cached, nil
+		}
+		return index{}, fmt.Errorf("gallery: failed to fetch index: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return loadCachedIndex()
+	}
+	if resp.StatusCode != http.StatusOK {
+		if cached, cerr := loadCachedIndex(); cerr == nil {
+			return cached, nil
+		}
+		return index{}, fmt.Errorf("gallery: unexpected status fetching index: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return index{}, err
+	}
+
+	var idx index
+	if strings.HasSuffix(url, ".json") {
+		err = json.Unmarshal(body, &idx)
+	} else {
+		err = yaml.Unmarshal(body, &idx)
+	}
+	if err != nil {
+		return index{}, fmt.Errorf("gallery: invalid index: %w", err)
+	}
+
+	cacheIndex(idx, resp.Header.Get("ETag"))
+	return idx, nil
+}
+
+func loadCachedIndex() (index, error) {
+	data, err := os.ReadFile(cachePath())
+	if err != nil {
+		return index{}, fmt.Errorf("gallery: no cached index available: %w", err)
+	}
+	var idx index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return index{}, fmt.Errorf("gallery: cached index is corrupt: %w", err)
+	}
+	return idx, nil
+}
+
+func cacheIndex(idx index, etag string) {
+	dir := CacheDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return
+	}
+	if data, err := json.MarshalIndent(idx, "", "  "); err == nil {
+		os.WriteFile(cachePath(), data, 0600)
+	}
+	if etag != "" {
+		os.WriteFile(etagPath(), []byte(etag), 0600)
+	}
+}
@@ -0,0 +1,246 @@
+package history
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// AgeHistoryPath returns the path to the age-encrypted, append-only history
+// store used whenever encryption is enabled.
+func AgeHistoryPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "./.uhh.history.age"
+	}
+	return filepath.Join(home, ".uhh.history.age")
+}
+
+// AgeKeyPath returns the path to the local X25519 identity used to decrypt
+// history. It is generated on first use and must stay private (0600).
+func AgeKeyPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "./.uhh/age.key"
+	}
+	return filepath.Join(home, ".uhh", "age.key")
+}
+
+// EncryptionEnabled reports whether history should be written age-encrypted.
+// Set UHH_HISTORY_ENCRYPT=false to opt out and fall back to plaintext.
+func EncryptionEnabled() bool {
+	v := strings.ToLower(os.Getenv("UHH_HISTORY_ENCRYPT"))
+	return v != "false" && v != "0"
+}
+
+// loadOrCreateIdentity reads the local age identity, generating and
+// persisting a new one on first use.
+func loadOrCreateIdentity() (*age.X25519Identity, error) {
+	keyPath := AgeKeyPath()
+
+	data, err := os.ReadFile(keyPath)
+	if err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			return age.ParseX25519Identity(line)
+		}
+		return nil, fmt.Errorf("age key file %s has no identity line", keyPath)
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate age identity: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(keyPath), 0700); err != nil {
+		return nil, err
+	}
+	contents := fmt.Sprintf("# created by uhh - keep this file private\n%s\n", identity.String())
+	if err := os.WriteFile(keyPath, []byte(contents), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write age identity: %w", err)
+	}
+
+	return identity, nil
+}
+
+// recipients returns the identity's own recipient plus any additional
+// recipients from UHH_AGE_RECIPIENTS (comma-separated age1... public keys),
+// so a team can share decryptable history logs.
+func recipients(identity *age.X25519Identity) ([]age.Recipient, error) {
+	recipients := []age.Recipient{identity.Recipient()}
+
+	extra := os.Getenv("UHH_AGE_RECIPIENTS")
+	if extra == "" {
+		return recipients, nil
+	}
+
+	for _, raw := range strings.Split(extra, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		recipient, err := age.ParseX25519Recipient(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid UHH_AGE_RECIPIENTS entry %q: %w", raw, err)
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	return recipients, nil
+}
+
+// appendEncryptedEntry encrypts entry into its own self-contained age
+// message and appends it to AgeHistoryPath(), framed with a 4-byte length
+// prefix. Because age's format isn't append-friendly, history is a sequence
+// of independently-encrypted blocks rather than one growing ciphertext, so
+// appending stays O(1) and never requires rewriting the file.
+func appendEncryptedEntry(entry Entry) error {
+	identity, err := loadOrCreateIdentity()
+	if err != nil {
+		return err
+	}
+	rcpts, err := recipients(identity)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, rcpts...)
+	if err != nil {
+		return fmt.Errorf("failed to start age encryption: %w", err)
+	}
+	payload, err := encodeEntry(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode history entry: %w", err)
+	}
+	if _, err := w.Write(payload); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to finalize age block: %w", err)
+	}
+
+	path := AgeHistoryPath()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(buf.Len()))
+	if _, err := f.Write(length[:]); err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// loadEncryptedEntries decrypts and parses blocks in AgeHistoryPath(), in
+// order. limit <= 0 reads every block; limit > 0 first scans the
+// length-prefixed blocks without decrypting them to find where the last
+// limit blocks begin, then decrypts only from there - avoiding the cost of
+// decrypting the whole history on every invocation once it grows large.
+func loadEncryptedEntries(limit int) ([]Entry, error) {
+	identity, err := loadOrCreateIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(AgeHistoryPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	start, err := encryptedTailOffset(f, limit)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for {
+		var length [4]byte
+		if _, err := io.ReadFull(f, length[:]); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("corrupt history block length: %w", err)
+		}
+
+		block := make([]byte, binary.BigEndian.Uint32(length[:]))
+		if _, err := io.ReadFull(f, block); err != nil {
+			return nil, fmt.Errorf("corrupt history block body: %w", err)
+		}
+
+		r, err := age.Decrypt(bytes.NewReader(block), identity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt history block: %w", err)
+		}
+		plaintext, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read decrypted history block: %w", err)
+		}
+
+		entries = append(entries, decodeEntry(plaintext))
+	}
+
+	return entries, nil
+}
+
+// encryptedTailOffset returns the byte offset of the first of the last
+// limit blocks in f, without decrypting any of them (limit <= 0 means the
+// whole file, offset 0). It leaves f's position unspecified; callers seek
+// explicitly before reading.
+func encryptedTailOffset(f *os.File, limit int) (int64, error) {
+	if limit <= 0 {
+		return 0, nil
+	}
+
+	var offsets []int64
+	for {
+		offset, err := f.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, err
+		}
+
+		var length [4]byte
+		if _, err := io.ReadFull(f, length[:]); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return 0, fmt.Errorf("corrupt history block length: %w", err)
+		}
+		offsets = append(offsets, offset)
+
+		if _, err := f.Seek(int64(binary.BigEndian.Uint32(length[:])), io.SeekCurrent); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(offsets) <= limit {
+		return 0, nil
+	}
+	return offsets[len(offsets)-limit], nil
+}
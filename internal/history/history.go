@@ -2,11 +2,14 @@ package history
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -16,9 +19,18 @@ type Entry struct {
 	Shell  string
 	Prompt string
 	Output string
+
+	// Usage is the token/cost accounting for the call that produced Output,
+	// if the caller has one (e.g. from agent.Result.Usage). Zero for callers
+	// that don't track usage.
+	Usage Usage
 }
 
-// GetHistoryPath returns the path to the history file
+// GetHistoryPath returns the path to the legacy plaintext/NDJSON history
+// file. LogEntry no longer writes here: the unencrypted path moved to the
+// SQLite Store, so this is only read as a fallback for an installation that
+// predates the store (see loadPlaintextEntries) or before any age-encrypted
+// history exists yet; see AgeHistoryPath for the default, encrypted store.
 func GetHistoryPath() string {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -27,24 +39,151 @@ func GetHistoryPath() string {
 	return filepath.Join(home, ".uhh.history.txt")
 }
 
-// LogEntry logs a history entry to the history file
+// historyRecordV1 is the versioned, JSON-encoded form of an Entry: the
+// payload of each age-encrypted block, and a single line of the legacy
+// plaintext history file. JSON replaces the original line-prefixed block
+// format (Time:/Shell:/Prompt:/Output:/---), which silently corrupted
+// whenever Output itself contained a line starting with one of those
+// prefixes or with "---" - common for commands that cat files or print
+// diffs. Marshaling keeps the whole record on one line (JSON escapes
+// embedded newlines), which also makes the plaintext file safe to read as
+// newline-delimited JSON.
+type historyRecordV1 struct {
+	V      int       `json:"v"`
+	Time   time.Time `json:"time"`
+	Shell  string    `json:"shell"`
+	Prompt string    `json:"prompt"`
+	Output string    `json:"output"`
+	Tokens Usage     `json:"tokens,omitempty"`
+}
+
+const historyRecordVersion = 1
+
+// encodeEntry renders entry as a single-line JSON record.
+func encodeEntry(entry Entry) ([]byte, error) {
+	return json.Marshal(historyRecordV1{
+		V:      historyRecordVersion,
+		Time:   entry.Time,
+		Shell:  entry.Shell,
+		Prompt: entry.Prompt,
+		Output: entry.Output,
+		Tokens: entry.Usage,
+	})
+}
+
+// decodeEntry parses a single history record. It accepts both the current
+// JSON format and, for data not yet upgraded by migrateLegacyPlaintextHistory
+// or an age block written before this format existed, the original
+// line-prefixed block format.
+func decodeEntry(data []byte) Entry {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return parseLegacyEntryLines(strings.Split(string(data), "\n"))
+	}
+
+	var rec historyRecordV1
+	if err := json.Unmarshal(trimmed, &rec); err != nil {
+		log.Printf("Warning: Failed to parse history record: %v", err)
+		return Entry{}
+	}
+	return Entry{Time: rec.Time, Shell: rec.Shell, Prompt: rec.Prompt, Output: rec.Output, Usage: rec.Tokens}
+}
+
+// parseLegacyEntryLines parses a single pre-JSON, formatEntry-shaped block
+// (Time:/Shell:/Prompt:/Output: lines, "---" stripped by the caller) back
+// into an Entry.
+func parseLegacyEntryLines(lines []string) Entry {
+	var entry Entry
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "Time: "):
+			if t, err := time.Parse(time.RFC3339, strings.TrimPrefix(line, "Time: ")); err == nil {
+				entry.Time = t
+			}
+		case strings.HasPrefix(line, "Shell: "):
+			entry.Shell = strings.TrimPrefix(line, "Shell: ")
+		case strings.HasPrefix(line, "Prompt: "):
+			entry.Prompt = strings.TrimPrefix(line, "Prompt: ")
+		case strings.HasPrefix(line, "Output: "):
+			entry.Output = strings.TrimPrefix(line, "Output: ")
+		}
+	}
+	return entry
+}
+
+var (
+	storeMu  sync.Mutex
+	storeVal *Store
+)
+
+// defaultStore lazily opens (or returns the already-open) SQLite store
+// backing the unencrypted history path.
+func defaultStore() (*Store, error) {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	if storeVal != nil {
+		return storeVal, nil
+	}
+	s, err := OpenStore(DefaultStorePath())
+	if err != nil {
+		return nil, err
+	}
+	storeVal = s
+	return s, nil
+}
+
+// closeDefaultStore closes and forgets the lazily-opened default store, so
+// Clear can delete its backing file and have the next write reopen a fresh
+// one.
+func closeDefaultStore() {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	if storeVal != nil {
+		storeVal.Close()
+		storeVal = nil
+	}
+}
+
+// LogEntry logs a history entry, encrypting it with age unless disabled via
+// UHH_HISTORY_ENCRYPT=false. An encrypted entry is encoded as a
+// historyRecordV1 JSON record and appended to AgeHistoryPath() as its own
+// age-wrapped block; encrypted history is kept out of the SQLite Store,
+// since storing it there would mean either keeping the database itself
+// encrypted at rest (at odds with SQLite needing random access) or
+// reimplementing row-level encryption.
+//
+// An unencrypted entry is written to the SQLite Store (see store.go) as a
+// new conversation holding one user message (the prompt) and one assistant
+// message (the output, with its usage). LogEntry never writes the legacy
+// plaintext file at GetHistoryPath(); loadPlaintextEntries only reads it,
+// one JSON record per line, tail-reading it with tailJSONLEntries.
 func LogEntry(entry Entry) {
-	histPath := GetHistoryPath()
-	f, err := os.OpenFile(histPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if EncryptionEnabled() {
+		if err := appendEncryptedEntry(entry); err != nil {
+			log.Printf("Warning: Failed to write encrypted history: %v", err)
+		}
+		return
+	}
+
+	store, err := defaultStore()
 	if err != nil {
-		log.Printf("Warning: Failed to write history: %v", err)
+		log.Printf("Warning: Failed to open history store: %v", err)
 		return
 	}
-	defer f.Close()
 
-	histEntry := fmt.Sprintf(
-		"Time: %s\nShell: %s\nPrompt: %s\nOutput: %s\n---\n",
-		entry.Time.Format(time.RFC3339),
-		entry.Shell,
-		entry.Prompt,
-		entry.Output,
-	)
-	fmt.Fprint(f, histEntry)
+	conv, err := store.NewConversation(entry.Shell)
+	if err != nil {
+		log.Printf("Warning: Failed to write history: %v", err)
+		return
+	}
+	userMsg, err := store.AppendMessage(conv.ID, nil, "user", entry.Prompt)
+	if err != nil {
+		log.Printf("Warning: Failed to write history: %v", err)
+		return
+	}
+	if _, err := store.AppendMessageWithUsage(conv.ID, &userMsg.ID, "assistant", entry.Output, entry.Usage); err != nil {
+		log.Printf("Warning: Failed to write history: %v", err)
+	}
 }
 
 // Log logs a simple history entry with the current time
@@ -57,33 +196,99 @@ func Log(shell, prompt, output string) {
 	})
 }
 
-// LoadLastEntry loads the last prompt and shell from history
-func LoadLastEntry() (prompt string, shell string) {
-	histPath := GetHistoryPath()
-	file, err := os.Open(histPath)
+// loadAllEntries returns up to the last limit history entries (limit <= 0
+// means every entry) in chronological order: the age-encrypted store when
+// it exists (regardless of whether encryption is currently enabled, so
+// disabling it later doesn't hide old encrypted history), else the SQLite
+// store, falling back to the legacy plaintext file for an installation that
+// predates the store.
+func loadAllEntries(limit int) []Entry {
+	if _, err := os.Stat(AgeHistoryPath()); err == nil {
+		entries, err := loadEncryptedEntries(limit)
+		if err != nil {
+			log.Printf("Warning: Failed to read encrypted history: %v", err)
+			return nil
+		}
+		return entries
+	}
+
+	if entries := loadStoreEntries(limit); len(entries) > 0 {
+		return entries
+	}
+
+	return loadPlaintextEntries(limit)
+}
+
+// loadStoreEntries converts the last limit conversations in the SQLite
+// store back into the legacy Entry shape, oldest first, assuming the Log
+// layout: a single user message followed by a single assistant reply.
+// limit <= 0 returns every conversation.
+func loadStoreEntries(limit int) []Entry {
+	store, err := defaultStore()
 	if err != nil {
-		return "", ""
+		log.Printf("Warning: Failed to open history store: %v", err)
+		return nil
 	}
-	defer file.Close()
 
-	var lastPrompt, lastShell string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.HasPrefix(line, "Prompt: ") {
-			lastPrompt = strings.TrimPrefix(line, "Prompt: ")
-		}
-		if strings.HasPrefix(line, "Shell: ") {
-			lastShell = strings.TrimPrefix(line, "Shell: ")
-		}
+	convs, err := store.List(limit)
+	if err != nil {
+		log.Printf("Warning: Failed to list history: %v", err)
+		return nil
 	}
 
-	return lastPrompt, lastShell
+	entries := make([]Entry, len(convs))
+	for i, conv := range convs {
+		// List returns newest-first; loadAllEntries' callers expect
+		// chronological order.
+		entries[len(convs)-1-i] = entryFromConversation(store, conv)
+	}
+	return entries
 }
 
-// LoadRecentEntries loads the N most recent history entries
-func LoadRecentEntries(n int) []Entry {
+// entryFromConversation reduces a conversation's stored messages back into
+// an Entry.
+func entryFromConversation(store *Store, conv Conversation) Entry {
+	entry := Entry{Time: conv.CreatedAt, Shell: conv.Shell}
+
+	_, messages, err := store.Load(conv.ID)
+	if err != nil {
+		return entry
+	}
+	for _, m := range messages {
+		switch m.Role {
+		case "user":
+			entry.Prompt = m.Content
+		case "assistant":
+			entry.Output = m.Content
+			entry.Usage = m.Usage
+		}
+	}
+	return entry
+}
+
+// loadPlaintextEntries reads the legacy, unencrypted history file - no
+// longer written by LogEntry since the unencrypted path moved to the
+// SQLite Store (see store.go), but still read as a fallback for an
+// installation that predates it. It migrates the file from the original
+// line-prefixed block format to one JSON record per line on first read
+// (see migrateLegacyPlaintextHistory), then, for limit > 0, tail-reads
+// just the last limit lines instead of scanning the whole file - this
+// matters once history has grown into the megabytes.
+func loadPlaintextEntries(limit int) []Entry {
 	histPath := GetHistoryPath()
+	if err := migrateLegacyPlaintextHistory(histPath); err != nil {
+		log.Printf("Warning: Failed to migrate legacy history file: %v", err)
+	}
+
+	if limit > 0 {
+		entries, err := tailJSONLEntries(histPath, limit)
+		if err != nil {
+			log.Printf("Warning: Failed to read history file: %v", err)
+			return nil
+		}
+		return entries
+	}
+
 	file, err := os.Open(histPath)
 	if err != nil {
 		return nil
@@ -91,46 +296,174 @@ func LoadRecentEntries(n int) []Entry {
 	defer file.Close()
 
 	var entries []Entry
-	var current Entry
-	var inEntry bool
-
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
-		line := scanner.Text()
+		if line := scanner.Text(); strings.TrimSpace(line) != "" {
+			entries = append(entries, decodeEntry([]byte(line)))
+		}
+	}
+	return entries
+}
 
-		if strings.HasPrefix(line, "Time: ") {
-			if inEntry {
-				entries = append(entries, current)
-			}
-			current = Entry{}
-			inEntry = true
-			timeStr := strings.TrimPrefix(line, "Time: ")
-			if t, err := time.Parse(time.RFC3339, timeStr); err == nil {
-				current.Time = t
-			}
-		} else if strings.HasPrefix(line, "Shell: ") {
-			current.Shell = strings.TrimPrefix(line, "Shell: ")
-		} else if strings.HasPrefix(line, "Prompt: ") {
-			current.Prompt = strings.TrimPrefix(line, "Prompt: ")
-		} else if strings.HasPrefix(line, "Output: ") {
-			current.Output = strings.TrimPrefix(line, "Output: ")
-		} else if line == "---" {
-			if inEntry {
-				entries = append(entries, current)
-				inEntry = false
+// migrateLegacyPlaintextHistory rewrites path in place from the original
+// line-prefixed block format (Time:/Shell:/Prompt:/Output:/---) to one JSON
+// record per line, if it isn't already. It's a no-op if path doesn't exist
+// or has already been migrated.
+func migrateLegacyPlaintextHistory(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] == '{' {
+		return nil
+	}
+
+	var entries []Entry
+	var current []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "---" {
+			if len(current) > 0 {
+				entries = append(entries, parseLegacyEntryLines(current))
+				current = nil
 			}
+			continue
 		}
+		current = append(current, line)
 	}
 
-	// Return last n entries
-	if len(entries) > n {
-		return entries[len(entries)-n:]
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		record, err := encodeEntry(entry)
+		if err != nil {
+			return fmt.Errorf("history: failed to migrate entry: %w", err)
+		}
+		buf.Write(record)
+		buf.WriteByte('\n')
 	}
-	return entries
+
+	return os.WriteFile(path, buf.Bytes(), 0600)
+}
+
+// tailJSONLEntries returns the last limit records of the NDJSON file at
+// path by seeking from the end and scanning backward for newlines, reading
+// only the tail chunk that contains them rather than the whole file.
+func tailJSONLEntries(path string, limit int) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	const chunkSize = 64 * 1024
+	var (
+		pos      = info.Size()
+		newlines = 0
+		tail     []byte
+	)
+	for pos > 0 && newlines <= limit {
+		readSize := int64(chunkSize)
+		if readSize > pos {
+			readSize = pos
+		}
+		pos -= readSize
+
+		buf := make([]byte, readSize)
+		if _, err := f.ReadAt(buf, pos); err != nil {
+			return nil, err
+		}
+		newlines += bytes.Count(buf, []byte("\n"))
+		tail = append(buf, tail...)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(tail), "\n"), "\n")
+	if len(lines) > limit {
+		lines = lines[len(lines)-limit:]
+	}
+
+	entries := make([]Entry, 0, len(lines))
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		entries = append(entries, decodeEntry([]byte(line)))
+	}
+	return entries, nil
 }
 
-// Clear clears the history file
+// LoadLastEntry loads the last prompt and shell from history
+func LoadLastEntry() (prompt string, shell string) {
+	entries := loadAllEntries(1)
+	if len(entries) == 0 {
+		return "", ""
+	}
+	last := entries[len(entries)-1]
+	return last.Prompt, last.Shell
+}
+
+// LoadRecentEntries loads the N most recent history entries
+func LoadRecentEntries(n int) []Entry {
+	if n <= 0 {
+		return nil
+	}
+	return loadAllEntries(n)
+}
+
+// UsageToday, UsageThisMonth, and UsageAllTime report cumulative token/cost
+// accounting from the unencrypted SQLite store, for the `uhh usage`
+// subcommand. They only see usage recorded via LogEntry's Usage field, so
+// totals are incomplete for any invocation that didn't supply one (e.g.
+// history predating this field, or callers whose underlying API doesn't
+// expose token counts).
+func UsageToday() (UsageTotal, error) {
+	store, err := defaultStore()
+	if err != nil {
+		return UsageTotal{}, err
+	}
+	return store.UsageToday()
+}
+
+func UsageThisMonth() (UsageTotal, error) {
+	store, err := defaultStore()
+	if err != nil {
+		return UsageTotal{}, err
+	}
+	return store.UsageThisMonth()
+}
+
+func UsageAllTime() (UsageTotal, error) {
+	store, err := defaultStore()
+	if err != nil {
+		return UsageTotal{}, err
+	}
+	return store.UsageAllTime()
+}
+
+// Clear clears all history: the plaintext file, the age-encrypted store,
+// and the SQLite store.
 func Clear() error {
-	histPath := GetHistoryPath()
-	return os.WriteFile(histPath, []byte{}, 0600)
+	if err := os.WriteFile(GetHistoryPath(), []byte{}, 0600); err != nil {
+		return err
+	}
+	if err := os.Remove(AgeHistoryPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	closeDefaultStore()
+	if err := os.Remove(DefaultStorePath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
 }
@@ -0,0 +1,446 @@
+package history
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// Store is a SQLite-backed replacement for the flat Time/Shell/Prompt/Output
+// text blocks the original encodeEntry/decodeEntry line format hand-parsed -
+// a format that silently corrupted on any Output containing a literal
+// "---" line (see historyRecordV1 in history.go for the JSON format that
+// replaced it for the age-encrypted and legacy-plaintext paths).
+// Conversation, Message, and ToolCall give each entry a real row, and a
+// Message's ParentID chains it into a DAG the same way agent.Context's
+// Nodes/ParentID do, so Fork can branch a new Conversation from any prior
+// message without disturbing the original.
+type Store struct {
+	db *sql.DB
+}
+
+// Conversation is one logged interaction - by default, a single shell
+// invocation's user/assistant message pair.
+type Conversation struct {
+	ID        int64
+	Shell     string
+	CreatedAt time.Time
+}
+
+// Message is a single turn within a Conversation.
+type Message struct {
+	ID             int64
+	ConversationID int64
+	ParentID       sql.NullInt64
+	Role           string
+	Content        string
+	CreatedAt      time.Time
+
+	// Usage is this message's token/cost accounting, if any was supplied via
+	// AppendMessageWithUsage - e.g. the agent.Usage of the round trip that
+	// produced an assistant message. Zero for messages logged without usage.
+	Usage Usage
+}
+
+// Usage is a message's token/cost accounting. It mirrors agent.Usage's
+// shape without this package depending on internal/agent - the same
+// boundary used for config.ModelPricing vs provider.ModelPricing - so
+// callers translate an agent.Usage into a history.Usage themselves.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+	CostUSD          float64
+}
+
+// UsageTotal aggregates Usage across a set of messages, e.g. for a time
+// window in UsageToday/UsageThisMonth/UsageAllTime.
+type UsageTotal struct {
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+	CostUSD          float64
+}
+
+// ToolCall records a single tool invocation attached to a Message.
+type ToolCall struct {
+	ID        int64
+	MessageID int64
+	ToolName  string
+	Input     string
+	Output    string
+}
+
+// DefaultStorePath returns ~/.uhh/history.db, the file OpenStore opens by
+// default.
+func DefaultStorePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "./.uhh.history.db"
+	}
+	return filepath.Join(home, ".uhh", "history.db")
+}
+
+const schema = `
+CREATE TABLE IF NOT EXISTS conversations (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	shell      TEXT NOT NULL DEFAULT '',
+	created_at TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS messages (
+	id                INTEGER PRIMARY KEY AUTOINCREMENT,
+	conversation_id   INTEGER NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+	parent_id         INTEGER REFERENCES messages(id) ON DELETE SET NULL,
+	role              TEXT NOT NULL,
+	content           TEXT NOT NULL,
+	created_at        TEXT NOT NULL,
+	prompt_tokens     INTEGER NOT NULL DEFAULT 0,
+	completion_tokens INTEGER NOT NULL DEFAULT 0,
+	total_tokens      INTEGER NOT NULL DEFAULT 0,
+	cost_usd          REAL NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS tool_calls (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+	tool_name  TEXT NOT NULL,
+	input      TEXT NOT NULL DEFAULT '',
+	output     TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_messages_conversation ON messages(conversation_id);
+CREATE INDEX IF NOT EXISTS idx_messages_parent ON messages(parent_id);
+`
+
+// OpenStore opens (creating if necessary) the SQLite database at path and
+// applies schema. schema is idempotent (CREATE TABLE/INDEX IF NOT EXISTS),
+// so it doubles as the only migration this schema has needed so far.
+func OpenStore(path string) (*Store, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("history: failed to create %s: %w", dir, err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("history: failed to open %s: %w", path, err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("history: failed to apply schema: %w", err)
+	}
+	if err := addUsageColumns(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// addUsageColumns adds the usage columns to a messages table created before
+// they existed (CREATE TABLE IF NOT EXISTS doesn't retrofit existing
+// tables), so upgrading an older ~/.uhh/history.db doesn't lose usage
+// tracking.
+func addUsageColumns(db *sql.DB) error {
+	rows, err := db.Query(`PRAGMA table_info(messages)`)
+	if err != nil {
+		return fmt.Errorf("history: failed to inspect messages schema: %w", err)
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var cid, notnull, pk int
+		var name, ctype string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &ctype, &notnull, &dflt, &pk); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[name] = true
+	}
+	rows.Close()
+
+	columns := []struct{ name, ddl string }{
+		{"prompt_tokens", "ALTER TABLE messages ADD COLUMN prompt_tokens INTEGER NOT NULL DEFAULT 0"},
+		{"completion_tokens", "ALTER TABLE messages ADD COLUMN completion_tokens INTEGER NOT NULL DEFAULT 0"},
+		{"total_tokens", "ALTER TABLE messages ADD COLUMN total_tokens INTEGER NOT NULL DEFAULT 0"},
+		{"cost_usd", "ALTER TABLE messages ADD COLUMN cost_usd REAL NOT NULL DEFAULT 0"},
+	}
+	for _, col := range columns {
+		if existing[col.name] {
+			continue
+		}
+		if _, err := db.Exec(col.ddl); err != nil {
+			return fmt.Errorf("history: failed to add column %s: %w", col.name, err)
+		}
+	}
+	return nil
+}
+
+// Close closes the underlying database connection.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// NewConversation inserts a new conversation row for shell and returns it.
+func (s *Store) NewConversation(shell string) (*Conversation, error) {
+	now := time.Now()
+	res, err := s.db.Exec(`INSERT INTO conversations (shell, created_at) VALUES (?, ?)`, shell, now.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("history: failed to create conversation: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return &Conversation{ID: id, Shell: shell, CreatedAt: now}, nil
+}
+
+// AppendMessage inserts a message under conversationID, optionally chained
+// to parentID, and returns the stored row. Equivalent to
+// AppendMessageWithUsage with a zero Usage.
+func (s *Store) AppendMessage(conversationID int64, parentID *int64, role, content string) (*Message, error) {
+	return s.AppendMessageWithUsage(conversationID, parentID, role, content, Usage{})
+}
+
+// AppendMessageWithUsage is AppendMessage, additionally recording usage
+// against the message - e.g. the agent.Usage of the round trip whose
+// response became this assistant message.
+func (s *Store) AppendMessageWithUsage(conversationID int64, parentID *int64, role, content string, usage Usage) (*Message, error) {
+	now := time.Now()
+	var parent sql.NullInt64
+	if parentID != nil {
+		parent = sql.NullInt64{Int64: *parentID, Valid: true}
+	}
+
+	res, err := s.db.Exec(
+		`INSERT INTO messages (conversation_id, parent_id, role, content, created_at, prompt_tokens, completion_tokens, total_tokens, cost_usd) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		conversationID, parent, role, content, now.Format(time.RFC3339),
+		usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens, usage.CostUSD,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("history: failed to append message: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return &Message{
+		ID: id, ConversationID: conversationID, ParentID: parent, Role: role, Content: content,
+		CreatedAt: now, Usage: usage,
+	}, nil
+}
+
+// AppendToolCall records a tool invocation against messageID.
+func (s *Store) AppendToolCall(messageID int64, toolName, input, output string) (*ToolCall, error) {
+	res, err := s.db.Exec(
+		`INSERT INTO tool_calls (message_id, tool_name, input, output) VALUES (?, ?, ?, ?)`,
+		messageID, toolName, input, output,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("history: failed to record tool call: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return &ToolCall{ID: id, MessageID: messageID, ToolName: toolName, Input: input, Output: output}, nil
+}
+
+// Fork creates a new conversation containing a copy of every message from
+// messageID's conversation root down to messageID (inclusive), preserving
+// their parent chain, so editing or re-prompting from that point doesn't
+// disturb the original conversation. AppendMessage the edited prompt onto
+// the returned conversation to continue from there.
+func (s *Store) Fork(messageID int64) (*Conversation, error) {
+	lineage, err := s.ancestry(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if len(lineage) == 0 {
+		return nil, fmt.Errorf("history: no such message: %d", messageID)
+	}
+
+	var shell string
+	if err := s.db.QueryRow(`SELECT shell FROM conversations WHERE id = ?`, lineage[0].ConversationID).Scan(&shell); err != nil {
+		return nil, fmt.Errorf("history: failed to load conversation for fork: %w", err)
+	}
+
+	conv, err := s.NewConversation(shell)
+	if err != nil {
+		return nil, err
+	}
+
+	var parent *int64
+	for _, m := range lineage {
+		copied, err := s.AppendMessage(conv.ID, parent, m.Role, m.Content)
+		if err != nil {
+			return nil, err
+		}
+		parent = &copied.ID
+	}
+
+	return conv, nil
+}
+
+// ancestry walks parent_id from messageID back to the root, returning the
+// chain root-first.
+func (s *Store) ancestry(messageID int64) ([]Message, error) {
+	var chain []Message
+	next := sql.NullInt64{Int64: messageID, Valid: true}
+	for next.Valid {
+		var m Message
+		var parent sql.NullInt64
+		var createdAt string
+		err := s.db.QueryRow(
+			`SELECT id, conversation_id, parent_id, role, content, created_at, prompt_tokens, completion_tokens, total_tokens, cost_usd FROM messages WHERE id = ?`,
+			next.Int64,
+		).Scan(&m.ID, &m.ConversationID, &parent, &m.Role, &m.Content, &createdAt,
+			&m.Usage.PromptTokens, &m.Usage.CompletionTokens, &m.Usage.TotalTokens, &m.Usage.CostUSD)
+		if err == sql.ErrNoRows {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("history: failed to walk ancestry: %w", err)
+		}
+		m.ParentID = parent
+		m.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		chain = append([]Message{m}, chain...)
+		next = parent
+	}
+	return chain, nil
+}
+
+// List returns the n most recently created conversations, newest first. A
+// non-positive n returns every conversation.
+func (s *Store) List(n int) ([]Conversation, error) {
+	query := `SELECT id, shell, created_at FROM conversations ORDER BY id DESC`
+	if n > 0 {
+		query += fmt.Sprintf(" LIMIT %d", n)
+	}
+
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("history: failed to list conversations: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Conversation
+	for rows.Next() {
+		var c Conversation
+		var createdAt string
+		if err := rows.Scan(&c.ID, &c.Shell, &createdAt); err != nil {
+			return nil, err
+		}
+		c.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// Load returns conversationID's messages in chronological (insertion)
+// order.
+func (s *Store) Load(conversationID int64) (*Conversation, []Message, error) {
+	var conv Conversation
+	var createdAt string
+	err := s.db.QueryRow(`SELECT id, shell, created_at FROM conversations WHERE id = ?`, conversationID).
+		Scan(&conv.ID, &conv.Shell, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, nil, fmt.Errorf("history: no such conversation: %d", conversationID)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	conv.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+
+	rows, err := s.db.Query(
+		`SELECT id, conversation_id, parent_id, role, content, created_at, prompt_tokens, completion_tokens, total_tokens, cost_usd FROM messages WHERE conversation_id = ? ORDER BY id ASC`,
+		conversationID,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("history: failed to load messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []Message
+	for rows.Next() {
+		var m Message
+		var parent sql.NullInt64
+		var ts string
+		if err := rows.Scan(&m.ID, &m.ConversationID, &parent, &m.Role, &m.Content, &ts,
+			&m.Usage.PromptTokens, &m.Usage.CompletionTokens, &m.Usage.TotalTokens, &m.Usage.CostUSD); err != nil {
+			return nil, nil, err
+		}
+		m.ParentID = parent
+		m.CreatedAt, _ = time.Parse(time.RFC3339, ts)
+		messages = append(messages, m)
+	}
+	return &conv, messages, rows.Err()
+}
+
+// Search returns messages whose content contains substr, most recent
+// first, capped at limit.
+func (s *Store) Search(substr string, limit int) ([]Message, error) {
+	rows, err := s.db.Query(
+		`SELECT id, conversation_id, parent_id, role, content, created_at, prompt_tokens, completion_tokens, total_tokens, cost_usd FROM messages WHERE content LIKE ? ESCAPE '\' ORDER BY id DESC LIMIT ?`,
+		"%"+escapeLike(substr)+"%", limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("history: search failed: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Message
+	for rows.Next() {
+		var m Message
+		var parent sql.NullInt64
+		var ts string
+		if err := rows.Scan(&m.ID, &m.ConversationID, &parent, &m.Role, &m.Content, &ts,
+			&m.Usage.PromptTokens, &m.Usage.CompletionTokens, &m.Usage.TotalTokens, &m.Usage.CostUSD); err != nil {
+			return nil, err
+		}
+		m.ParentID = parent
+		m.CreatedAt, _ = time.Parse(time.RFC3339, ts)
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// UsageToday, UsageThisMonth, and UsageAllTime sum every message's recorded
+// Usage within the given window, for the `uhh usage` report.
+func (s *Store) UsageToday() (UsageTotal, error) {
+	return s.usageWhere(`date(created_at) = date('now')`)
+}
+
+func (s *Store) UsageThisMonth() (UsageTotal, error) {
+	return s.usageWhere(`strftime('%Y-%m', created_at) = strftime('%Y-%m', 'now')`)
+}
+
+func (s *Store) UsageAllTime() (UsageTotal, error) {
+	return s.usageWhere(`1 = 1`)
+}
+
+func (s *Store) usageWhere(where string) (UsageTotal, error) {
+	var t UsageTotal
+	query := fmt.Sprintf(
+		`SELECT COALESCE(SUM(prompt_tokens),0), COALESCE(SUM(completion_tokens),0), COALESCE(SUM(total_tokens),0), COALESCE(SUM(cost_usd),0) FROM messages WHERE %s`,
+		where,
+	)
+	err := s.db.QueryRow(query).Scan(&t.PromptTokens, &t.CompletionTokens, &t.TotalTokens, &t.CostUSD)
+	if err != nil {
+		return UsageTotal{}, fmt.Errorf("history: failed to sum usage: %w", err)
+	}
+	return t, nil
+}
+
+// escapeLike escapes substr's SQL LIKE metacharacters so Search treats it
+// as a literal substring.
+func escapeLike(substr string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return r.Replace(substr)
+}
@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"github.com/tmc/langchaingo/llms/anthropic"
+)
+
+const anthropicDefaultModel = "claude-3-5-sonnet-20241022"
+
+// AnthropicProvider implements Provider for Anthropic's Claude models
+type AnthropicProvider struct {
+	BaseProvider
+}
+
+// Initialize sets up the Anthropic provider
+func (p *AnthropicProvider) Initialize(cfg Config) error {
+	p.name = "anthropic"
+	p.displayName = "Anthropic"
+	p.config = cfg
+
+	model := cfg.Model
+	if model == "" {
+		model = anthropicDefaultModel
+	}
+
+	opts := []anthropic.Option{
+		anthropic.WithModel(model),
+	}
+
+	if cfg.BaseURL != "" {
+		opts = append(opts, anthropic.WithBaseURL(cfg.BaseURL))
+	}
+
+	if cfg.APIKey != "" {
+		opts = append(opts, anthropic.WithToken(cfg.APIKey))
+	}
+
+	llm, err := anthropic.New(opts...)
+	if err != nil {
+		return err
+	}
+
+	p.llm = llm
+	return nil
+}
+
+// SupportsToolCalling returns true as Claude supports tool use
+func (p *AnthropicProvider) SupportsToolCalling() bool {
+	return true
+}
@@ -1,11 +1,7 @@
 package provider
 
-import (
-	"github.com/tmc/langchaingo/llms/openai"
-)
-
 const (
-	deepseekBaseURL     = "https://api.deepseek.com/v1"
+	deepseekBaseURL      = "https://api.deepseek.com/v1"
 	deepseekDefaultModel = "deepseek-chat"
 )
 
@@ -20,26 +16,7 @@ func (p *DeepseekProvider) Initialize(cfg Config) error {
 	p.displayName = "DeepSeek"
 	p.config = cfg
 
-	model := cfg.Model
-	if model == "" {
-		model = deepseekDefaultModel
-	}
-
-	baseURL := cfg.BaseURL
-	if baseURL == "" {
-		baseURL = deepseekBaseURL
-	}
-
-	opts := []openai.Option{
-		openai.WithModel(model),
-		openai.WithBaseURL(baseURL),
-	}
-
-	if cfg.APIKey != "" {
-		opts = append(opts, openai.WithToken(cfg.APIKey))
-	}
-
-	llm, err := openai.New(opts...)
+	llm, err := NewOpenAICompatible(cfg, Defaults{Model: deepseekDefaultModel, BaseURL: deepseekBaseURL})
 	if err != nil {
 		return err
 	}
@@ -1,9 +1,5 @@
 package provider
 
-import (
-	"github.com/tmc/langchaingo/llms/openai"
-)
-
 const (
 	glmBaseURL      = "https://open.bigmodel.cn/api/paas/v4"
 	glmDefaultModel = "glm-4"
@@ -20,26 +16,7 @@ func (p *GLMProvider) Initialize(cfg Config) error {
 	p.displayName = "GLM (Zhipu AI)"
 	p.config = cfg
 
-	model := cfg.Model
-	if model == "" {
-		model = glmDefaultModel
-	}
-
-	baseURL := cfg.BaseURL
-	if baseURL == "" {
-		baseURL = glmBaseURL
-	}
-
-	opts := []openai.Option{
-		openai.WithModel(model),
-		openai.WithBaseURL(baseURL),
-	}
-
-	if cfg.APIKey != "" {
-		opts = append(opts, openai.WithToken(cfg.APIKey))
-	}
-
-	llm, err := openai.New(opts...)
+	llm, err := NewOpenAICompatible(cfg, Defaults{Model: glmDefaultModel, BaseURL: glmBaseURL})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,376 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"uhh/internal/provider/grpcpb"
+
+	"github.com/tmc/langchaingo/llms"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// GRPCProvider talks to a local inference backend (llama.cpp, vLLM, LocalAI,
+// ...) over gRPC instead of an OpenAI-compatible HTTP shim, following the
+// same backend-behind-gRPC pattern LocalAI uses. The endpoint is the
+// provider's BaseURL; an optional bearer token can be supplied via APIKey.
+type GRPCProvider struct {
+	name        string
+	displayName string
+	config      Config
+
+	conn   *grpc.ClientConn
+	client grpcpb.InferenceServiceClient
+
+	supportsTools bool
+}
+
+// NewGRPCProvider creates a provider bound to the given registry name.
+func NewGRPCProvider(name, displayName string) *GRPCProvider {
+	return &GRPCProvider{name: name, displayName: displayName}
+}
+
+func (p *GRPCProvider) Name() string        { return p.name }
+func (p *GRPCProvider) DisplayName() string { return p.displayName }
+
+// Model returns the model name this provider was Initialize'd with.
+func (p *GRPCProvider) Model() string { return p.config.Model }
+
+// Initialize dials cfg.BaseURL and performs a Health + Capabilities
+// handshake, caching the result so SupportsToolCalling() doesn't need a
+// round trip on every call. BaseURL's scheme selects how the backend is
+// reached: "grpc://host:port" dials directly, "exec:///path/to/backend
+// [args...]" spawns the backend as a subprocess serving InferenceService
+// over a derived Unix socket (killed on provider.Shutdown), and anything
+// else (a bare "host:port" or "unix:///path") is passed to grpc.NewClient
+// unchanged, as before. Either way the connection forces grpcpb.JSONCodec
+// (see its doc comment), so a spawned exec:// backend must speak that same
+// JSON-over-gRPC wire format, not raw protobuf.
+func (p *GRPCProvider) Initialize(cfg Config) error {
+	if cfg.BaseURL == "" {
+		return fmt.Errorf("grpc provider %s: BaseURL (gRPC address) is required", p.name)
+	}
+	p.config = cfg
+
+	target := cfg.BaseURL
+	switch {
+	case strings.HasPrefix(target, "exec://"):
+		spawned, cmd, err := spawnExecBackend(p.name, strings.TrimPrefix(target, "exec://"))
+		if err != nil {
+			return fmt.Errorf("grpc provider %s: %w", p.name, err)
+		}
+		trackActiveProcess(cmd)
+		target = spawned
+	case strings.HasPrefix(target, "grpc://"):
+		target = strings.TrimPrefix(target, "grpc://")
+	}
+
+	// Plaintext is the common case for a locally-run backend. ForceCodec is
+	// required: grpcpb's message types aren't real proto.Message
+	// implementations, so grpc-go's default codec can't marshal them (see
+	// grpcpb.JSONCodec's doc comment).
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.ForceCodec(grpcpb.JSONCodec{}),
+	}
+
+	conn, err := grpc.NewClient(target, dialOpts...)
+	if err != nil {
+		return fmt.Errorf("grpc provider %s: failed to dial %s: %w", p.name, target, err)
+	}
+	p.conn = conn
+	p.client = grpcpb.NewInferenceServiceClient(conn)
+
+	ctx := p.authContext(context.Background())
+
+	health, err := p.client.Health(ctx, &grpcpb.HealthRequest{})
+	if err != nil {
+		return fmt.Errorf("grpc provider %s: health check failed: %w", p.name, err)
+	}
+	if !health.Ok {
+		return fmt.Errorf("grpc provider %s: backend unhealthy: %s", p.name, health.Message)
+	}
+
+	caps, err := p.client.Capabilities(ctx, &grpcpb.CapabilitiesRequest{})
+	if err != nil {
+		return fmt.Errorf("grpc provider %s: capabilities check failed: %w", p.name, err)
+	}
+	p.supportsTools = caps.SupportsToolCalling
+
+	return nil
+}
+
+// authContext attaches the optional bearer token from Config.APIKey.
+func (p *GRPCProvider) authContext(ctx context.Context) context.Context {
+	if p.config.APIKey == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+p.config.APIKey)
+}
+
+// LLM returns an llms.Model adapter backed by this gRPC connection so Call
+// and GenerateContent can share BaseProvider-style plumbing where useful.
+func (p *GRPCProvider) LLM() llms.Model {
+	return (*grpcModelAdapter)(p)
+}
+
+func (p *GRPCProvider) SupportsToolCalling() bool {
+	return p.supportsTools
+}
+
+func (p *GRPCProvider) Call(ctx context.Context, prompt string, opts ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, p.LLM(), prompt, opts...)
+}
+
+func (p *GRPCProvider) GenerateContent(ctx context.Context, messages []llms.MessageContent, opts ...llms.CallOption) (*llms.ContentResponse, error) {
+	return p.LLM().GenerateContent(ctx, messages, opts...)
+}
+
+// Stream dials Predict and emits each chunk as it arrives over the channel,
+// giving the interactive path in main.go real token-level streaming instead
+// of the single-shot emulation BaseProvider.Stream falls back to.
+func (p *GRPCProvider) Stream(ctx context.Context, prompt string, opts ...llms.CallOption) (<-chan Chunk, error) {
+	callOpts := &llms.CallOptions{}
+	for _, opt := range opts {
+		opt(callOpts)
+	}
+
+	req := &grpcpb.PredictRequest{
+		Messages:    []*grpcpb.Message{{Role: "user", Content: prompt}},
+		Temperature: float32(callOpts.Temperature),
+		MaxTokens:   int32(callOpts.MaxTokens),
+	}
+
+	stream, err := p.client.Predict(p.authContext(ctx), req)
+	if err != nil {
+		return nil, fmt.Errorf("grpc provider %s: predict failed: %w", p.name, err)
+	}
+
+	ch := make(chan Chunk)
+	go func() {
+		defer close(ch)
+		for {
+			chunk, err := stream.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				select {
+				case ch <- Chunk{Err: err}:
+				case <-ctx.Done():
+				}
+				return
+			}
+			if chunk.Error != "" {
+				select {
+				case ch <- Chunk{Err: fmt.Errorf("%s", chunk.Error)}:
+				case <-ctx.Done():
+				}
+				return
+			}
+			if chunk.Text != "" {
+				select {
+				case ch <- Chunk{Text: chunk.Text}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if chunk.Done {
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// spawnExecBackend parses rawCmd (the part of an exec:// BaseURL after the
+// scheme: a command and its whitespace-separated arguments) and starts it as
+// a subprocess serving grpcpb.InferenceService over a derived Unix socket
+// named by UHH_PLUGIN_SOCKET, waiting for the socket to appear before
+// returning a "unix://" target GRPCProvider can dial directly.
+func spawnExecBackend(name, rawCmd string) (target string, cmd *exec.Cmd, err error) {
+	fields := strings.Fields(rawCmd)
+	if len(fields) == 0 {
+		return "", nil, fmt.Errorf("exec:// BaseURL must name a command")
+	}
+
+	socket := filepath.Join(os.TempDir(), fmt.Sprintf("uhh-exec-%s.sock", name))
+	os.Remove(socket)
+
+	c := exec.Command(fields[0], fields[1:]...)
+	c.Env = append(os.Environ(), "UHH_PLUGIN_SOCKET="+socket)
+	c.Stderr = os.Stderr
+
+	if err := c.Start(); err != nil {
+		return "", nil, fmt.Errorf("failed to start %s: %w", fields[0], err)
+	}
+	if err := waitForSocket(socket, pluginStartTimeout); err != nil {
+		c.Process.Kill()
+		return "", nil, err
+	}
+
+	return "unix://" + socket, c, nil
+}
+
+// grpcModelAdapter makes GRPCProvider satisfy llms.Model by translating a
+// single-shot GenerateContent call into a Predict stream collected to completion.
+type grpcModelAdapter GRPCProvider
+
+func (a *grpcModelAdapter) GenerateContent(ctx context.Context, messages []llms.MessageContent, opts ...llms.CallOption) (*llms.ContentResponse, error) {
+	return (*GRPCProvider)(a).predict(ctx, messages, opts, nil)
+}
+
+// GenerateContentStream implements Provider.GenerateContentStream by
+// invoking handler for each Predict chunk's text as it arrives - real
+// token-level streaming, since the gRPC backend sends one PredictChunk per
+// token rather than the single final response GenerateContent collects.
+func (p *GRPCProvider) GenerateContentStream(ctx context.Context, messages []llms.MessageContent, opts []llms.CallOption, handler func(ctx context.Context, chunk []byte) error) (*llms.ContentResponse, error) {
+	return p.predict(ctx, messages, opts, handler)
+}
+
+// predict drives a single Predict call, accumulating streamed chunks into a
+// final ContentResponse. When handler is non-nil, it's invoked with each
+// chunk's text as it arrives, before being appended to the accumulated text.
+func (p *GRPCProvider) predict(ctx context.Context, messages []llms.MessageContent, opts []llms.CallOption, handler func(ctx context.Context, chunk []byte) error) (*llms.ContentResponse, error) {
+	callOpts := &llms.CallOptions{}
+	for _, opt := range opts {
+		opt(callOpts)
+	}
+
+	req := &grpcpb.PredictRequest{
+		Messages:    toGRPCMessages(messages),
+		Temperature: float32(callOpts.Temperature),
+		MaxTokens:   int32(callOpts.MaxTokens),
+	}
+	if len(callOpts.Tools) > 0 {
+		req.Tools = toGRPCTools(callOpts.Tools)
+	}
+
+	stream, err := p.client.Predict(p.authContext(ctx), req)
+	if err != nil {
+		return nil, fmt.Errorf("grpc provider %s: predict failed: %w", p.name, err)
+	}
+
+	var text string
+	var toolCalls []llms.ToolCall
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if chunk.Error != "" {
+			return nil, fmt.Errorf("%s", chunk.Error)
+		}
+		if chunk.Text != "" {
+			if handler != nil {
+				if err := handler(ctx, []byte(chunk.Text)); err != nil {
+					return nil, err
+				}
+			}
+			text += chunk.Text
+		}
+		if chunk.ToolCall != nil {
+			toolCalls = append(toolCalls, llms.ToolCall{
+				ID: chunk.ToolCall.Id,
+				FunctionCall: &llms.FunctionCall{
+					Name:      chunk.ToolCall.Name,
+					Arguments: chunk.ToolCall.ArgumentsJson,
+				},
+			})
+		}
+		if chunk.Done {
+			break
+		}
+	}
+
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{
+			Content:   text,
+			ToolCalls: toolCalls,
+		}},
+	}, nil
+}
+
+// toGRPCMessages translates langchaingo messages to the wire format,
+// including tool calls and tool results - not just text - so a backend
+// driving a multi-turn tool-calling conversation sees what it called and
+// what came back on later turns, not just the plain-text parts.
+func toGRPCMessages(messages []llms.MessageContent) []*grpcpb.Message {
+	out := make([]*grpcpb.Message, 0, len(messages))
+	for _, m := range messages {
+		var content string
+		var toolCalls []*grpcpb.ToolCall
+		var toolResponses []llms.ToolCallResponse
+
+		for _, part := range m.Parts {
+			switch p := part.(type) {
+			case llms.TextContent:
+				content += p.Text
+			case llms.ToolCall:
+				toolCalls = append(toolCalls, toGRPCToolCall(p))
+			case llms.ToolCallResponse:
+				toolResponses = append(toolResponses, p)
+			}
+		}
+
+		if len(toolResponses) == 0 {
+			out = append(out, &grpcpb.Message{
+				Role:      string(m.Role),
+				Content:   content,
+				ToolCalls: toolCalls,
+			})
+			continue
+		}
+
+		// A tool-role message can batch more than one ToolCallResponse (see
+		// Context.toLangchainMessages' Anthropic-specific merging); the
+		// backend gets one grpcpb.Message per call result instead, since
+		// that's the shape PredictRequest.Messages is meant to carry.
+		for _, tr := range toolResponses {
+			out = append(out, &grpcpb.Message{
+				Role:       string(m.Role),
+				Content:    tr.Content,
+				ToolCallId: tr.ToolCallID,
+				ToolName:   tr.Name,
+			})
+		}
+	}
+	return out
+}
+
+func toGRPCToolCall(tc llms.ToolCall) *grpcpb.ToolCall {
+	out := &grpcpb.ToolCall{Id: tc.ID}
+	if tc.FunctionCall != nil {
+		out.Name = tc.FunctionCall.Name
+		out.ArgumentsJson = tc.FunctionCall.Arguments
+	}
+	return out
+}
+
+func toGRPCTools(tools []llms.Tool) []*grpcpb.ToolSpec {
+	out := make([]*grpcpb.ToolSpec, 0, len(tools))
+	for _, tool := range tools {
+		if tool.Function == nil {
+			continue
+		}
+		paramsJSON, _ := json.Marshal(tool.Function.Parameters)
+		out = append(out, &grpcpb.ToolSpec{
+			Name:           tool.Function.Name,
+			Description:    tool.Function.Description,
+			ParametersJson: string(paramsJSON),
+		})
+	}
+	return out
+}
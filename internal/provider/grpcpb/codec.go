@@ -0,0 +1,36 @@
+package grpcpb
+
+import "encoding/json"
+
+// JSONCodec implements google.golang.org/grpc/encoding.Codec (Marshal,
+// Unmarshal, Name) using encoding/json instead of the protobuf wire format.
+//
+// The message types in this package (HealthRequest, PredictChunk, ...) are
+// hand-written Go structs with no protobuf field tags and no Reset/String/
+// ProtoReflect methods - they don't implement proto.Message. grpc-go's
+// default codec type-asserts every request/response to proto.Message and
+// fails otherwise, so every InferenceServiceClient/Server call must be
+// forced onto this codec instead via grpc.ForceCodec (dial option) and
+// grpc.ForceServerCodec (server option).
+//
+// A consequence: this service's wire format is JSON, not protobuf, despite
+// inference.proto describing the message shapes in proto3 syntax. A
+// plugin written in another language must match that - encode/decode each
+// RPC's request/response as a single JSON object keyed by the Go field names
+// in inference.pb.go (e.g. "ToolCallId", "ArgumentsJson"), since those
+// structs carry no json tags and encoding/json uses the field name as-is
+// (decoding matches keys case-insensitively), not run the
+// .proto through its own protoc-gen-* and speak real protobuf on the wire.
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (JSONCodec) Name() string {
+	return "json"
+}
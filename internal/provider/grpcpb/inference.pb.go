@@ -0,0 +1,64 @@
+// Hand-written message types mirroring inference.proto. protoc-gen-go isn't
+// available in this build environment, so these are plain structs rather
+// than real generated proto.Message implementations; see codec.go for the
+// JSONCodec that lets them travel over gRPC anyway, and its doc comment for
+// what that means for non-Go backends. Regenerate properly with:
+//   protoc --go_out=. --go-grpc_out=. inference.proto
+// once protoc-gen-go/protoc-gen-go-grpc are available, and delete codec.go.
+
+package grpcpb
+
+type HealthRequest struct{}
+
+type HealthResponse struct {
+	Ok      bool
+	Message string
+}
+
+type CapabilitiesRequest struct{}
+
+type CapabilitiesResponse struct {
+	SupportsToolCalling bool
+	Models              []string
+}
+
+type Message struct {
+	Role    string
+	Content string
+
+	// ToolCalls is set when Role is "assistant" and the message is the tool
+	// calls an earlier turn made.
+	ToolCalls []*ToolCall
+
+	// ToolCallId and ToolName are set when Role is "tool": which call this
+	// message answers, and the name of the tool that was invoked. Content
+	// above carries the tool's result.
+	ToolCallId string
+	ToolName   string
+}
+
+type ToolCall struct {
+	Id            string
+	Name          string
+	ArgumentsJson string
+}
+
+type ToolSpec struct {
+	Name           string
+	Description    string
+	ParametersJson string
+}
+
+type PredictRequest struct {
+	Messages    []*Message
+	Tools       []*ToolSpec
+	Temperature float32
+	MaxTokens   int32
+}
+
+type PredictChunk struct {
+	Text     string
+	ToolCall *ToolCall
+	Done     bool
+	Error    string
+}
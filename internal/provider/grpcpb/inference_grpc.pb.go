@@ -0,0 +1,156 @@
+// Hand-written client/server stubs mirroring inference.proto's service
+// definition. protoc-gen-go-grpc isn't available in this build environment;
+// see inference.pb.go's header and codec.go for why these messages ride a
+// JSON codec rather than real protobuf encoding.
+
+package grpcpb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// InferenceServiceClient is the client API for InferenceService.
+type InferenceServiceClient interface {
+	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
+	Capabilities(ctx context.Context, in *CapabilitiesRequest, opts ...grpc.CallOption) (*CapabilitiesResponse, error)
+	Predict(ctx context.Context, in *PredictRequest, opts ...grpc.CallOption) (InferenceService_PredictClient, error)
+}
+
+type inferenceServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+// NewInferenceServiceClient wraps a dialed connection in the generated client.
+func NewInferenceServiceClient(cc *grpc.ClientConn) InferenceServiceClient {
+	return &inferenceServiceClient{cc}
+}
+
+func (c *inferenceServiceClient) Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error) {
+	out := new(HealthResponse)
+	if err := c.cc.Invoke(ctx, "/grpcpb.InferenceService/Health", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inferenceServiceClient) Capabilities(ctx context.Context, in *CapabilitiesRequest, opts ...grpc.CallOption) (*CapabilitiesResponse, error) {
+	out := new(CapabilitiesResponse)
+	if err := c.cc.Invoke(ctx, "/grpcpb.InferenceService/Capabilities", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inferenceServiceClient) Predict(ctx context.Context, in *PredictRequest, opts ...grpc.CallOption) (InferenceService_PredictClient, error) {
+	stream, err := c.cc.NewStream(ctx, &grpc.StreamDesc{StreamName: "Predict", ServerStreams: true}, "/grpcpb.InferenceService/Predict", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &inferenceServicePredictClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// InferenceService_PredictClient is the stream handle returned by Predict.
+type InferenceService_PredictClient interface {
+	Recv() (*PredictChunk, error)
+	grpc.ClientStream
+}
+
+type inferenceServicePredictClient struct {
+	grpc.ClientStream
+}
+
+func (x *inferenceServicePredictClient) Recv() (*PredictChunk, error) {
+	m := new(PredictChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// InferenceServiceServer is the server API for InferenceService.
+type InferenceServiceServer interface {
+	Health(context.Context, *HealthRequest) (*HealthResponse, error)
+	Capabilities(context.Context, *CapabilitiesRequest) (*CapabilitiesResponse, error)
+	Predict(*PredictRequest, InferenceService_PredictServer) error
+}
+
+// InferenceService_PredictServer is the stream handle passed to server implementations.
+type InferenceService_PredictServer interface {
+	Send(*PredictChunk) error
+	grpc.ServerStream
+}
+
+type inferenceServicePredictServer struct {
+	grpc.ServerStream
+}
+
+func (x *inferenceServicePredictServer) Send(m *PredictChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _InferenceService_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InferenceServiceServer).Health(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/grpcpb.InferenceService/Health"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InferenceServiceServer).Health(ctx, req.(*HealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InferenceService_Capabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InferenceServiceServer).Capabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/grpcpb.InferenceService/Capabilities"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InferenceServiceServer).Capabilities(ctx, req.(*CapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InferenceService_Predict_Handler(srv interface{}, stream grpc.ServerStream) error {
+	in := new(PredictRequest)
+	if err := stream.RecvMsg(in); err != nil {
+		return err
+	}
+	return srv.(InferenceServiceServer).Predict(in, &inferenceServicePredictServer{stream})
+}
+
+// _InferenceService_serviceDesc describes InferenceService for grpc.Server.RegisterService.
+var _InferenceService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "grpcpb.InferenceService",
+	HandlerType: (*InferenceServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Health", Handler: _InferenceService_Health_Handler},
+		{MethodName: "Capabilities", Handler: _InferenceService_Capabilities_Handler},
+	},
+	Streams: []grpc.StreamDesc{
+		{StreamName: "Predict", Handler: _InferenceService_Predict_Handler, ServerStreams: true},
+	},
+	Metadata: "inference.proto",
+}
+
+// RegisterInferenceServiceServer registers impl with srv so incoming calls to
+// InferenceService are routed to it.
+func RegisterInferenceServiceServer(srv *grpc.Server, impl InferenceServiceServer) {
+	srv.RegisterService(&_InferenceService_serviceDesc, impl)
+}
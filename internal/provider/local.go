@@ -0,0 +1,32 @@
+package provider
+
+// LocalProvider implements Provider for self-hosted OpenAI-compatible
+// backends - Ollama, LM Studio, vLLM, or any other server that exposes a
+// /v1/chat/completions endpoint. Unlike the hosted providers it has no
+// fixed base URL or API key requirement; both come from cfg, set during
+// onboarding (see tui.RunOnboarding's local-provider branch).
+type LocalProvider struct {
+	BaseProvider
+}
+
+// Initialize sets up the local provider
+func (p *LocalProvider) Initialize(cfg Config) error {
+	p.name = "local"
+	p.displayName = "Local / Self-hosted"
+	p.config = cfg
+
+	llm, err := NewOpenAICompatible(cfg, Defaults{})
+	if err != nil {
+		return err
+	}
+
+	p.llm = llm
+	return nil
+}
+
+// SupportsToolCalling returns true; most self-hosted OpenAI-compatible
+// servers (Ollama, LM Studio, vLLM) implement the same function-calling
+// wire format as OpenAI.
+func (p *LocalProvider) SupportsToolCalling() bool {
+	return true
+}
@@ -37,11 +37,37 @@ func ListModelsOpenAI(ctx context.Context, apiKey, baseURL string) ([]ModelInfo,
 		baseURL = "https://api.openai.com/v1"
 	}
 
+	all, err := fetchOpenAICompatibleModels(ctx, baseURL, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]ModelInfo, 0, len(all))
+	for _, m := range all {
+		// Filter to only include chat/completion models
+		if isRelevantModel(m.ID) {
+			models = append(models, m)
+		}
+	}
+
+	sort.Slice(models, func(i, j int) bool {
+		return models[i].ID < models[j].ID
+	})
+
+	return models, nil
+}
+
+// fetchOpenAICompatibleModels calls GET baseURL+"/models" - the endpoint
+// every OpenAI-compatible server (OpenAI itself, DeepSeek, GLM, Ollama, LM
+// Studio, vLLM) implements - and returns the raw model list, unfiltered.
+func fetchOpenAICompatibleModels(ctx context.Context, baseURL, apiKey string) ([]ModelInfo, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/models", nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+apiKey)
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
 
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
@@ -61,14 +87,79 @@ func ListModelsOpenAI(ctx context.Context, apiKey, baseURL string) ([]ModelInfo,
 
 	models := make([]ModelInfo, 0, len(result.Data))
 	for _, m := range result.Data {
-		// Filter to only include chat/completion models
-		if isRelevantModel(m.ID) {
-			models = append(models, ModelInfo{
-				ID:      m.ID,
-				Name:    m.ID,
-				OwnedBy: m.OwnedBy,
-			})
-		}
+		models = append(models, ModelInfo{
+			ID:      m.ID,
+			Name:    m.ID,
+			OwnedBy: m.OwnedBy,
+		})
+	}
+
+	return models, nil
+}
+
+// ListModelsFromBaseURL fetches the model list from a self-hosted
+// OpenAI-compatible backend at baseURL (Ollama, LM Studio, vLLM, or
+// anything else exposing /v1/models), unfiltered since self-hosted model
+// names don't follow any of the naming conventions isRelevantModel checks.
+// If the standard /v1/models call fails, it falls back to Ollama's native
+// GET /api/tags, since Ollama's OpenAI-compatible surface doesn't always
+// mirror its native one exactly.
+func ListModelsFromBaseURL(ctx context.Context, baseURL, apiKey string) ([]ModelInfo, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("baseURL is required")
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	models, err := fetchOpenAICompatibleModels(ctx, baseURL, apiKey)
+	if err == nil {
+		sort.Slice(models, func(i, j int) bool {
+			return models[i].ID < models[j].ID
+		})
+		return models, nil
+	}
+
+	if tagModels, tagErr := listOllamaTags(ctx, baseURL); tagErr == nil {
+		return tagModels, nil
+	}
+
+	return nil, err
+}
+
+// listOllamaTags fetches Ollama's native GET /api/tags, which lives at the
+// server root rather than under /v1. baseURL is typically the OpenAI-shim
+// path (e.g. "http://localhost:11434/v1"), so the /v1 suffix is stripped
+// before appending /api/tags.
+func listOllamaTags(ctx context.Context, baseURL string) ([]ModelInfo, error) {
+	root := strings.TrimSuffix(baseURL, "/v1")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", root+"/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Ollama API returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	models := make([]ModelInfo, 0, len(result.Models))
+	for _, m := range result.Models {
+		models = append(models, ModelInfo{ID: m.Name, Name: m.Name})
 	}
 
 	sort.Slice(models, func(i, j int) bool {
@@ -78,6 +169,31 @@ func ListModelsOpenAI(ctx context.Context, apiKey, baseURL string) ([]ModelInfo,
 	return models, nil
 }
 
+// ProbeBaseURL pings baseURL's /models endpoint with a short timeout to
+// check whether a local/self-hosted server is actually running, so
+// onboarding can warn the user immediately instead of failing later with a
+// confusing connection-refused error mid-conversation. Any HTTP response at
+// all (even a 4xx from a server that doesn't recognize the path) counts as
+// reachable; only a transport-level failure is treated as unreachable.
+func ProbeBaseURL(ctx context.Context, baseURL string) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", strings.TrimSuffix(baseURL, "/")+"/models", nil)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 3 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not reach %s: %w", baseURL, err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
 // ListModelsGemini fetches available models from Google Gemini API
 func ListModelsGemini(ctx context.Context, apiKey string) ([]ModelInfo, error) {
 	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models?key=%s", apiKey)
@@ -222,6 +338,18 @@ func ListModels(ctx context.Context, providerName, apiKey, baseURL string) ([]Mo
 		return ListModelsKimi(ctx, apiKey)
 	case "glm":
 		return ListModelsGLM(ctx, apiKey)
+	case "local":
+		return ListModelsFromBaseURL(ctx, baseURL, apiKey)
+	case "ollama":
+		if baseURL == "" {
+			baseURL = ollamaBaseURL
+		}
+		return ListModelsFromBaseURL(ctx, baseURL, apiKey)
+	case "anthropic":
+		// Anthropic has no public, unauthenticated models-list endpoint in
+		// the same vein as OpenAI's; callers fall back to
+		// GetDefaultModelsForProvider.
+		return nil, fmt.Errorf("anthropic does not support listing models")
 	default:
 		return nil, fmt.Errorf("unknown provider: %s", providerName)
 	}
@@ -305,6 +433,18 @@ func GetDefaultModelsForProvider(providerName string) []ModelInfo {
 			{ID: "glm-4-flash", Name: "GLM-4 Flash", Description: "Fast GLM-4"},
 			{ID: "glm-3-turbo", Name: "GLM-3 Turbo", Description: "Efficient model"},
 		}
+	case "anthropic":
+		return []ModelInfo{
+			{ID: "claude-3-5-sonnet-20241022", Name: "Claude 3.5 Sonnet", Description: "Most capable Claude model"},
+			{ID: "claude-3-5-haiku-20241022", Name: "Claude 3.5 Haiku", Description: "Fast and efficient"},
+			{ID: "claude-3-opus-20240229", Name: "Claude 3 Opus", Description: "Previous flagship model"},
+		}
+	case "ollama":
+		return []ModelInfo{
+			{ID: "llama3.1", Name: "Llama 3.1", Description: "Meta's Llama 3.1"},
+			{ID: "mistral", Name: "Mistral", Description: "Mistral 7B"},
+			{ID: "qwen2.5-coder", Name: "Qwen2.5 Coder", Description: "Code-focused model"},
+		}
 	default:
 		return nil
 	}
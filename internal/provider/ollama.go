@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"github.com/tmc/langchaingo/llms/ollama"
+)
+
+const (
+	ollamaBaseURL      = "http://localhost:11434"
+	ollamaDefaultModel = "llama3.1"
+)
+
+// OllamaProvider implements Provider for a locally-running Ollama server.
+// It's distinct from the generic LocalProvider: Ollama speaks its own
+// native API rather than the OpenAI-compatible one, so it gets a
+// first-class entry with its own defaults instead of going through
+// NewOpenAICompatible.
+type OllamaProvider struct {
+	BaseProvider
+}
+
+// Initialize sets up the Ollama provider
+func (p *OllamaProvider) Initialize(cfg Config) error {
+	p.name = "ollama"
+	p.displayName = "Ollama"
+	p.config = cfg
+
+	model := cfg.Model
+	if model == "" {
+		model = ollamaDefaultModel
+	}
+
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = ollamaBaseURL
+	}
+
+	llm, err := ollama.New(
+		ollama.WithModel(model),
+		ollama.WithServerURL(baseURL),
+	)
+	if err != nil {
+		return err
+	}
+
+	p.llm = llm
+	return nil
+}
+
+// SupportsToolCalling returns true; recent Ollama models (e.g. llama3.1+)
+// support function calling through the same tool-call wire format.
+func (p *OllamaProvider) SupportsToolCalling() bool {
+	return true
+}
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/openai"
 )
 
@@ -15,33 +16,55 @@ func (p *OpenAIProvider) Initialize(cfg Config) error {
 	p.displayName = "OpenAI"
 	p.config = cfg
 
+	llm, err := NewOpenAICompatible(cfg, Defaults{Model: "gpt-4o"})
+	if err != nil {
+		return err
+	}
+
+	p.llm = llm
+	return nil
+}
+
+// SupportsToolCalling returns true as OpenAI supports function calling
+func (p *OpenAIProvider) SupportsToolCalling() bool {
+	return true
+}
+
+// Defaults supplies fallback values for NewOpenAICompatible when a
+// provider's Config doesn't set its own Model/BaseURL.
+type Defaults struct {
+	Model   string
+	BaseURL string
+}
+
+// NewOpenAICompatible builds a langchaingo OpenAI-compatible LLM client from
+// cfg, falling back to defaults.Model/defaults.BaseURL when cfg leaves them
+// empty. It's shared by every provider that just talks to an OpenAI-shaped
+// /v1/chat/completions endpoint - OpenAI itself, DeepSeek, GLM, and
+// local/self-hosted backends - so each only supplies its own name, display
+// name, and defaults.
+func NewOpenAICompatible(cfg Config, defaults Defaults) (llms.Model, error) {
 	model := cfg.Model
 	if model == "" {
-		model = "gpt-4o"
+		model = defaults.Model
 	}
 
-	opts := []openai.Option{
-		openai.WithModel(model),
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaults.BaseURL
 	}
 
-	if cfg.APIKey != "" {
-		opts = append(opts, openai.WithToken(cfg.APIKey))
+	opts := []openai.Option{
+		openai.WithModel(model),
 	}
 
-	if cfg.BaseURL != "" {
-		opts = append(opts, openai.WithBaseURL(cfg.BaseURL))
+	if baseURL != "" {
+		opts = append(opts, openai.WithBaseURL(baseURL))
 	}
 
-	llm, err := openai.New(opts...)
-	if err != nil {
-		return err
+	if cfg.APIKey != "" {
+		opts = append(opts, openai.WithToken(cfg.APIKey))
 	}
 
-	p.llm = llm
-	return nil
-}
-
-// SupportsToolCalling returns true as OpenAI supports function calling
-func (p *OpenAIProvider) SupportsToolCalling() bool {
-	return true
+	return openai.New(opts...)
 }
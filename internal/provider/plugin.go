@@ -0,0 +1,204 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// pluginStartTimeout bounds how long Load waits for a spawned plugin to
+// create its Unix socket before giving up on it.
+const pluginStartTimeout = 5 * time.Second
+
+// PluginManifest describes a provider plugin binary discovered under
+// PluginsDir(): a subprocess that serves grpcpb.InferenceService over a Unix
+// socket named by the UHH_PLUGIN_SOCKET environment variable, as implemented
+// by pkg/provider/plugin.Serve for Go-based plugins. Plugins in other
+// languages implement the same service described by
+// internal/provider/grpcpb/inference.proto, encoding each RPC as a JSON
+// object rather than protobuf bytes - see grpcpb.JSONCodec's doc comment.
+type PluginManifest struct {
+	Name        string            `json:"name"`
+	DisplayName string            `json:"display_name,omitempty"`
+	Command     string            `json:"command"`
+	Args        []string          `json:"args,omitempty"`
+	Env         map[string]string `json:"env,omitempty"`
+}
+
+// PluginsDir returns ~/.uhh/plugins, the directory Load scans at startup.
+func PluginsDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "./.uhh/plugins"
+	}
+	return filepath.Join(home, ".uhh", "plugins")
+}
+
+var (
+	activeMu      sync.Mutex
+	activePlugins []*exec.Cmd
+)
+
+// Load scans PluginsDir() for subdirectories containing a manifest.json,
+// spawns each as a subprocess exposing a Unix-socket gRPC server, health
+// checks it, and registers it in the global registry under the name declared
+// in its manifest. Errors are collected rather than returned eagerly so one
+// broken plugin doesn't stop the built-ins (or other plugins) from loading.
+func Load() []error {
+	dir := PluginsDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return []error{fmt.Errorf("provider: failed to read plugins dir %s: %w", dir, err)}
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		pluginDir := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(filepath.Join(pluginDir, "manifest.json"))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("plugin[%s]: failed to read manifest.json: %w", entry.Name(), err))
+			continue
+		}
+
+		var manifest PluginManifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			errs = append(errs, fmt.Errorf("plugin[%s]: invalid manifest.json: %w", entry.Name(), err))
+			continue
+		}
+		if manifest.Name == "" || manifest.Command == "" {
+			errs = append(errs, fmt.Errorf("plugin[%s]: manifest.json must declare name and command", entry.Name()))
+			continue
+		}
+
+		command := manifest.Command
+		if !filepath.IsAbs(command) {
+			command = filepath.Join(pluginDir, command)
+		}
+
+		if err := spawnAndRegister(manifest.Name, manifest.DisplayName, command, manifest.Args, manifest.Env, pluginDir, ""); err != nil {
+			errs = append(errs, fmt.Errorf("plugin[%s]: %w", manifest.Name, err))
+		}
+	}
+
+	return errs
+}
+
+// RegisterCommand spawns command as a subprocess plugin and registers it
+// under name, for providers declared directly via a ProviderSettings'
+// Command/Args/Env/Socket fields in config rather than discovered under
+// PluginsDir(). It's a no-op if name is already registered (e.g. a built-in,
+// or a plugin Load already found).
+func RegisterCommand(name, command string, args []string, env map[string]string, socket string) error {
+	if Has(name) {
+		return nil
+	}
+	return spawnAndRegister(name, name, command, args, env, "", socket)
+}
+
+// spawnAndRegister starts command as a subprocess listening on socket (a
+// default derived from name if empty), health-checks it over gRPC, and
+// registers a GRPCProvider bound to that socket under name in the global
+// registry. dir sets the subprocess's working directory.
+func spawnAndRegister(name, displayName, command string, args []string, env map[string]string, dir, socket string) error {
+	if displayName == "" {
+		displayName = name
+	}
+	if socket == "" {
+		socket = filepath.Join(os.TempDir(), fmt.Sprintf("uhh-plugin-%s.sock", name))
+	}
+	os.Remove(socket)
+
+	cmd := exec.Command(command, args...)
+	cmd.Dir = dir
+	cmd.Env = os.Environ()
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	cmd.Env = append(cmd.Env, "UHH_PLUGIN_SOCKET="+socket)
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", command, err)
+	}
+
+	if err := waitForSocket(socket, pluginStartTimeout); err != nil {
+		cmd.Process.Kill()
+		return err
+	}
+
+	probe := &pluginProvider{GRPCProvider: NewGRPCProvider(name, displayName), socket: socket}
+	if err := probe.Initialize(Config{}); err != nil {
+		cmd.Process.Kill()
+		return fmt.Errorf("health check failed: %w", err)
+	}
+
+	trackActiveProcess(cmd)
+
+	Register(name, func() Provider {
+		return &pluginProvider{GRPCProvider: NewGRPCProvider(name, displayName), socket: socket}
+	})
+
+	return nil
+}
+
+// pluginProvider wraps a GRPCProvider bound to a fixed Unix socket, so the
+// generic BaseURL from provider.Config (which plugin ProviderSettings don't
+// set - they use Command/Socket instead) never overrides where the plugin
+// subprocess actually listens.
+type pluginProvider struct {
+	*GRPCProvider
+	socket string
+}
+
+func (p *pluginProvider) Initialize(cfg Config) error {
+	cfg.BaseURL = "unix://" + p.socket
+	return p.GRPCProvider.Initialize(cfg)
+}
+
+// waitForSocket polls for path to appear, giving the plugin subprocess time
+// to start listening before the first gRPC dial.
+func waitForSocket(path string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(path); err == nil {
+			return nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return fmt.Errorf("plugin did not create socket %s within %s", path, timeout)
+}
+
+// trackActiveProcess records cmd so Shutdown kills it, for any subprocess
+// backend spawned outside of Load/RegisterCommand's own registration (e.g.
+// GRPCProvider.Initialize spawning an exec:// BaseURL directly).
+func trackActiveProcess(cmd *exec.Cmd) {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	activePlugins = append(activePlugins, cmd)
+}
+
+// Shutdown terminates every plugin subprocess spawned by Load or
+// RegisterCommand. Callers should defer this once at startup, after
+// resolving providers, so plugin processes don't outlive the uhh invocation
+// that spawned them.
+func Shutdown() {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	for _, cmd := range activePlugins {
+		if cmd.Process != nil {
+			cmd.Process.Kill()
+		}
+	}
+	activePlugins = nil
+}
@@ -0,0 +1,45 @@
+package provider
+
+// ModelPricing is the $ cost per 1,000 tokens for a single model's input
+// and output tokens.
+type ModelPricing struct {
+	InputPer1K  float64
+	OutputPer1K float64
+}
+
+// PricingTable maps a model name to its ModelPricing, typically built at
+// startup from config.Config.Pricing.
+type PricingTable map[string]ModelPricing
+
+// UsageFromGenerationInfo extracts prompt/completion/total token counts from
+// a langchaingo ContentChoice's GenerationInfo map (the shape common
+// langchaingo backends populate: "PromptTokens", "CompletionTokens",
+// "TotalTokens"). A provider that doesn't populate GenerationInfo yields
+// all zeros, which is safe to fold into an aggregate.
+func UsageFromGenerationInfo(info map[string]interface{}) (promptTokens, completionTokens, totalTokens int) {
+	intVal := func(key string) int {
+		switch n := info[key].(type) {
+		case int:
+			return n
+		case int64:
+			return int(n)
+		case float64:
+			return int(n)
+		default:
+			return 0
+		}
+	}
+	return intVal("PromptTokens"), intVal("CompletionTokens"), intVal("TotalTokens")
+}
+
+// Cost estimates the $ cost of promptTokens/completionTokens against model.
+// It returns 0 if model has no entry in the table (e.g. pricing wasn't
+// configured for it), rather than erroring, since cost reporting is best
+// effort and shouldn't block on unpriced models.
+func (t PricingTable) Cost(model string, promptTokens, completionTokens int) float64 {
+	p, ok := t[model]
+	if !ok {
+		return 0
+	}
+	return float64(promptTokens)/1000*p.InputPer1K + float64(completionTokens)/1000*p.OutputPer1K
+}
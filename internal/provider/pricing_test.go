@@ -0,0 +1,35 @@
+package provider
+
+import "testing"
+
+func TestPricingTableCost(t *testing.T) {
+	table := PricingTable{
+		"gpt-4o": {InputPer1K: 0.005, OutputPer1K: 0.015},
+	}
+
+	got := table.Cost("gpt-4o", 1000, 1000)
+	want := 0.005 + 0.015
+	if got != want {
+		t.Fatalf("Cost() = %v, want %v", got, want)
+	}
+
+	if got := table.Cost("unknown-model", 1000, 1000); got != 0 {
+		t.Fatalf("Cost() for unpriced model = %v, want 0", got)
+	}
+}
+
+func TestUsageFromGenerationInfo(t *testing.T) {
+	prompt, completion, total := UsageFromGenerationInfo(map[string]interface{}{
+		"PromptTokens":     10,
+		"CompletionTokens": 5,
+		"TotalTokens":      15,
+	})
+	if prompt != 10 || completion != 5 || total != 15 {
+		t.Fatalf("UsageFromGenerationInfo() = (%d, %d, %d)", prompt, completion, total)
+	}
+
+	prompt, completion, total = UsageFromGenerationInfo(nil)
+	if prompt != 0 || completion != 0 || total != 0 {
+		t.Fatalf("UsageFromGenerationInfo(nil) = (%d, %d, %d), want all zero", prompt, completion, total)
+	}
+}
@@ -23,11 +23,36 @@ type Provider interface {
 	// SupportsToolCalling returns whether this provider supports function calling
 	SupportsToolCalling() bool
 
+	// Model returns the model name this provider was Initialize'd with, for
+	// callers that need to look it up in a PricingTable.
+	Model() string
+
 	// Call makes a simple text completion call
 	Call(ctx context.Context, prompt string, opts ...llms.CallOption) (string, error)
 
 	// GenerateContent makes a content generation call with messages
 	GenerateContent(ctx context.Context, messages []llms.MessageContent, opts ...llms.CallOption) (*llms.ContentResponse, error)
+
+	// Stream makes a completion call and emits text deltas as they arrive.
+	// Providers that don't natively stream emit a single chunk with the
+	// full response, so callers can always range over the channel safely.
+	Stream(ctx context.Context, prompt string, opts ...llms.CallOption) (<-chan Chunk, error)
+
+	// GenerateContentStream is GenerateContent's tool-call-aware counterpart:
+	// it invokes handler with each chunk of assistant text as it arrives
+	// (synchronously, on the caller's goroutine) and returns the same
+	// *llms.ContentResponse GenerateContent would once the call completes, so
+	// agent.Agent can render deltas live while still getting a complete
+	// ContentResponse (including any ToolCalls) to act on. Providers whose
+	// underlying langchaingo model doesn't support incremental streaming
+	// still work correctly: handler is just invoked once with the full text.
+	GenerateContentStream(ctx context.Context, messages []llms.MessageContent, opts []llms.CallOption, handler func(ctx context.Context, chunk []byte) error) (*llms.ContentResponse, error)
+}
+
+// Chunk is a single piece of a streamed completion.
+type Chunk struct {
+	Text string
+	Err  error
 }
 
 // Config contains configuration for a provider
@@ -57,6 +82,11 @@ func (p *BaseProvider) DisplayName() string {
 	return p.displayName
 }
 
+// Model returns the model name this provider was Initialize'd with.
+func (p *BaseProvider) Model() string {
+	return p.config.Model
+}
+
 // LLM returns the underlying LLM model
 func (p *BaseProvider) LLM() llms.Model {
 	return p.llm
@@ -71,3 +101,71 @@ func (p *BaseProvider) Call(ctx context.Context, prompt string, opts ...llms.Cal
 func (p *BaseProvider) GenerateContent(ctx context.Context, messages []llms.MessageContent, opts ...llms.CallOption) (*llms.ContentResponse, error) {
 	return p.llm.GenerateContent(ctx, messages, opts...)
 }
+
+// Stream makes a completion call, emitting text deltas over the returned
+// channel as they arrive and closing it when the call finishes. Providers
+// whose underlying langchaingo implementation doesn't support incremental
+// streaming still work correctly: they just emit the whole response as one
+// chunk.
+func (p *BaseProvider) Stream(ctx context.Context, prompt string, opts ...llms.CallOption) (<-chan Chunk, error) {
+	ch := make(chan Chunk)
+
+	var streamed bool
+	streamOpts := append(append([]llms.CallOption{}, opts...), llms.WithStreamingFunc(func(ctx context.Context, delta []byte) error {
+		streamed = true
+		select {
+		case ch <- Chunk{Text: string(delta)}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}))
+
+	go func() {
+		defer close(ch)
+		text, err := llms.GenerateFromSinglePrompt(ctx, p.llm, prompt, streamOpts...)
+		if err != nil {
+			select {
+			case ch <- Chunk{Err: err}:
+			case <-ctx.Done():
+			}
+			return
+		}
+		// streamed is only ever set from WithStreamingFunc's callback, which
+		// GenerateFromSinglePrompt invokes synchronously on this same
+		// goroutine, so it's safe to read now without synchronization. If
+		// it never fired, the underlying langchaingo model doesn't support
+		// incremental streaming - fall back to the whole response as one
+		// chunk instead of silently emitting nothing.
+		if !streamed && text != "" {
+			select {
+			case ch <- Chunk{Text: text}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// GenerateContentStream wraps p.llm.GenerateContent with
+// llms.WithStreamingFunc, invoking handler for each text delta as it arrives
+// and returning the full response once the call completes. If the model
+// never streamed, handler is invoked once with the first choice's content.
+func (p *BaseProvider) GenerateContentStream(ctx context.Context, messages []llms.MessageContent, opts []llms.CallOption, handler func(ctx context.Context, chunk []byte) error) (*llms.ContentResponse, error) {
+	var streamed bool
+	streamOpts := append(append([]llms.CallOption{}, opts...), llms.WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
+		streamed = true
+		return handler(ctx, chunk)
+	}))
+	resp, err := p.llm.GenerateContent(ctx, messages, streamOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if !streamed && len(resp.Choices) > 0 && resp.Choices[0].Content != "" {
+		if err := handler(ctx, []byte(resp.Choices[0].Content)); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
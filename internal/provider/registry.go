@@ -105,4 +105,8 @@ func init() {
 	Register("deepseek", func() Provider { return &DeepseekProvider{} })
 	Register("kimi", func() Provider { return &KimiProvider{} })
 	Register("glm", func() Provider { return &GLMProvider{} })
+	Register("local", func() Provider { return &LocalProvider{} })
+	Register("anthropic", func() Provider { return &AnthropicProvider{} })
+	Register("ollama", func() Provider { return &OllamaProvider{} })
+	Register("grpc", func() Provider { return NewGRPCProvider("grpc", "Local gRPC Backend") })
 }
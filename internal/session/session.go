@@ -0,0 +1,141 @@
+// Package session persists agent.Context DAGs to disk as JSON files, one
+// per conversation, so a uhh session can be resumed, listed, inspected, or
+// pruned across process restarts via the `uhh session` subcommands.
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"uhh/internal/agent"
+	"uhh/internal/config"
+)
+
+// Info is session metadata surfaced by List without loading the full DAG.
+type Info struct {
+	ID        string
+	UpdatedAt time.Time
+	Preview   string
+}
+
+// Dir returns the directory sessions are stored under, creating it if
+// needed.
+func Dir() (string, error) {
+	configDir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(configDir, "sessions")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+func pathFor(id string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, id+".json"), nil
+}
+
+// Save persists ctx under id, overwriting any existing session with that
+// ID.
+func Save(id string, ctx *agent.Context) error {
+	path, err := pathFor(id)
+	if err != nil {
+		return err
+	}
+	return ctx.Save(path)
+}
+
+// Load reads back a session previously written by Save.
+func Load(id string) (*agent.Context, error) {
+	path, err := pathFor(id)
+	if err != nil {
+		return nil, err
+	}
+	return agent.LoadContext(path)
+}
+
+// List returns metadata for every saved session, most recently updated
+// first.
+func List() ([]Info, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]Info, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		id := strings.TrimSuffix(entry.Name(), ".json")
+		ctx, err := Load(id)
+		if err != nil {
+			continue
+		}
+
+		var updatedAt time.Time
+		if fi, err := entry.Info(); err == nil {
+			updatedAt = fi.ModTime()
+		}
+
+		infos = append(infos, Info{
+			ID:        id,
+			UpdatedAt: updatedAt,
+			Preview:   preview(ctx),
+		})
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].UpdatedAt.After(infos[j].UpdatedAt)
+	})
+
+	return infos, nil
+}
+
+// preview returns the first user message on the active branch, trimmed for
+// single-line display in `session list`.
+func preview(ctx *agent.Context) string {
+	for _, msg := range ctx.Path() {
+		if msg.Role != "user" {
+			continue
+		}
+		text := strings.ReplaceAll(msg.Content, "\n", " ")
+		if len(text) > 60 {
+			text = text[:60] + "..."
+		}
+		return text
+	}
+	return ""
+}
+
+// Remove deletes the saved session with the given id.
+func Remove(id string) error {
+	path, err := pathFor(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no such session: %s", id)
+		}
+		return err
+	}
+	return nil
+}
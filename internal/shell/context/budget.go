@@ -0,0 +1,73 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// bytesPerToken is the same rough approximation the rest of the codebase
+// uses when it doesn't have a real tokenizer on hand.
+const bytesPerToken = 4
+
+// FileContext is one resolved file selected for prompt injection.
+type FileContext struct {
+	Path    string
+	Content string
+}
+
+// SelectFileContexts resolves prompt against idx and reads files in
+// resolution order until maxTokens is spent, so the combined injected
+// content fits the budget as a whole rather than each file being checked
+// against it in isolation (which could still blow the budget once several
+// small files are appended together).
+func (idx *Index) SelectFileContexts(prompt string, maxTokens int) []FileContext {
+	paths := idx.Resolve(prompt)
+	if len(paths) == 0 {
+		return nil
+	}
+
+	remaining := maxTokens * bytesPerToken
+	var selected []FileContext
+
+	for _, rel := range paths {
+		if remaining <= 0 {
+			break
+		}
+
+		abs := filepath.Join(idx.root, rel)
+		info, err := os.Stat(abs)
+		if err != nil || info.IsDir() || int(info.Size()) > remaining {
+			continue
+		}
+
+		data, err := os.ReadFile(abs)
+		if err != nil || len(data) > remaining {
+			continue
+		}
+
+		selected = append(selected, FileContext{Path: rel, Content: string(data)})
+		remaining -= len(data)
+	}
+
+	return selected
+}
+
+// Render formats contexts as the <file_contexts> block injected into the LLM
+// prompt.
+func Render(contexts []FileContext) string {
+	if len(contexts) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<file_contexts>\n")
+	for _, c := range contexts {
+		sb.WriteString(fmt.Sprintf("<file name='%s'>\n", c.Path))
+		sb.WriteString(c.Content)
+		sb.WriteString("\n</file>\n")
+	}
+	sb.WriteString("</file_contexts>\n")
+	return sb.String()
+}
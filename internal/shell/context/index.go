@@ -0,0 +1,70 @@
+// Package context builds a workspace-aware index of real files so prompt
+// building can resolve file references the user actually typed, instead of
+// guessing from regexes that false-positive on things like "v1.0" or
+// "foo.bar" in prose.
+package context
+
+import (
+	"os"
+	"path/filepath"
+
+	gitignore "github.com/sabhiram/go-gitignore"
+)
+
+// Index is a one-shot snapshot of every non-ignored file under a workspace
+// root, used to resolve tokens from a user prompt against real paths.
+type Index struct {
+	root   string
+	paths  []string // every indexed path, relative to root
+	byBase *trie    // basename -> relative paths, for exact-match lookups
+}
+
+// Build walks root once, honoring .gitignore (and always skipping .git),
+// and returns an Index of every file found. It never fails on a missing or
+// unreadable .gitignore; it just falls back to indexing everything.
+func Build(root string) (*Index, error) {
+	ignore := loadIgnore(root)
+
+	idx := &Index{root: root, byBase: newTrie()}
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // unreadable entries are skipped, not fatal
+		}
+
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil || rel == "." {
+			return nil
+		}
+
+		if info.IsDir() {
+			if rel == ".git" || ignore.MatchesPath(rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if ignore.MatchesPath(rel) {
+			return nil
+		}
+
+		idx.paths = append(idx.paths, rel)
+		idx.byBase.insert(filepath.Base(rel), rel)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return idx, nil
+}
+
+// loadIgnore compiles root/.gitignore, falling back to an empty ignore set
+// (matches nothing) when the file doesn't exist or fails to parse.
+func loadIgnore(root string) *gitignore.GitIgnore {
+	ignore, err := gitignore.CompileIgnoreFile(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		ignore, _ = gitignore.CompileIgnoreLines()
+	}
+	return ignore
+}
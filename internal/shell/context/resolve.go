@@ -0,0 +1,69 @@
+package context
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/sahilm/fuzzy"
+)
+
+// minFuzzyScore is the lowest sahilm/fuzzy match score accepted as a real
+// file reference. It's tuned conservatively so prose words don't fuzzy-match
+// an unrelated file just because they share a few letters.
+const minFuzzyScore = 10
+
+// Resolve finds real files referenced by tokens in prompt: an exact basename
+// hit wins outright, otherwise a token that looks like a filename is fuzzy
+// matched against every indexed path. Order follows first mention in prompt.
+func (idx *Index) Resolve(prompt string) []string {
+	seen := make(map[string]bool)
+	var matches []string
+
+	add := func(rel string) {
+		if !seen[rel] {
+			seen[rel] = true
+			matches = append(matches, rel)
+		}
+	}
+
+	for _, token := range tokenize(prompt) {
+		if exact := idx.byBase.lookup(token); len(exact) > 0 {
+			for _, rel := range exact {
+				add(rel)
+			}
+			continue
+		}
+
+		if !looksLikeFilename(token) {
+			continue
+		}
+
+		results := fuzzy.Find(token, idx.paths)
+		if len(results) == 0 {
+			continue
+		}
+		sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+
+		if best := results[0]; best.Score >= minFuzzyScore {
+			add(idx.paths[best.Index])
+		}
+	}
+
+	return matches
+}
+
+// tokenize splits a prompt into candidate words, trimming the punctuation
+// that quoting or sentence structure tends to wrap around a filename (e.g.
+// "'main.go'," or "(config.yaml)").
+func tokenize(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return strings.ContainsRune(" \n\t,;:()\"'", r)
+	})
+}
+
+// looksLikeFilename filters out plain English words before they reach fuzzy
+// matching, so "fix the config" doesn't fuzzy-match some unrelated
+// config.yaml just because "config" is a prefix of it.
+func looksLikeFilename(token string) bool {
+	return strings.ContainsAny(token, "./\\") || strings.Contains(token, ".")
+}
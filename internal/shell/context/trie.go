@@ -0,0 +1,45 @@
+package context
+
+// trieNode is a node in the basename trie, keyed one byte at a time.
+type trieNode struct {
+	children map[byte]*trieNode
+	paths    []string // relative paths whose basename ends exactly here
+}
+
+// trie indexes file basenames for O(len(key)) exact lookups, so a token like
+// "main.go" resolves to every path in the workspace named that without
+// scanning the whole file list.
+type trie struct {
+	root *trieNode
+}
+
+func newTrie() *trie {
+	return &trie{root: &trieNode{children: make(map[byte]*trieNode)}}
+}
+
+func (t *trie) insert(basename, path string) {
+	node := t.root
+	for i := 0; i < len(basename); i++ {
+		b := basename[i]
+		child, ok := node.children[b]
+		if !ok {
+			child = &trieNode{children: make(map[byte]*trieNode)}
+			node.children[b] = child
+		}
+		node = child
+	}
+	node.paths = append(node.paths, path)
+}
+
+// lookup returns every path whose basename exactly matches key.
+func (t *trie) lookup(key string) []string {
+	node := t.root
+	for i := 0; i < len(key); i++ {
+		child, ok := node.children[key[i]]
+		if !ok {
+			return nil
+		}
+		node = child
+	}
+	return node.paths
+}
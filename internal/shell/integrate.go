@@ -0,0 +1,321 @@
+package shell
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// markerStart and markerEnd delimit the block InstallIntegration manages
+// inside an rc file, so Install/Uninstall can find and replace it
+// idempotently without touching anything else the user has added.
+const (
+	markerStart = "# >>> uhh >>>"
+	markerEnd   = "# <<< uhh <<<"
+)
+
+// IntegrationOptions configures the snippet InstallIntegration writes.
+type IntegrationOptions struct {
+	// BinaryPath is the uhh executable to reference in the generated
+	// snippet. Defaults to "uhh" (resolved via $PATH) when empty.
+	BinaryPath string
+
+	// Hotkey is the readline/PSReadLine key binding used to insert the last
+	// generated command into the current line. Defaults to "\C-x\C-h" for
+	// Bash/Zsh and "Ctrl+x,Ctrl+h" for PowerShell when empty.
+	Hotkey string
+}
+
+func (o IntegrationOptions) binary() string {
+	if o.BinaryPath != "" {
+		return o.BinaryPath
+	}
+	return "uhh"
+}
+
+// InstallIntegration writes an idempotent shell integration block for the
+// given shell (Bash, Zsh, Fish, or PowerShell). Bash/Zsh/PowerShell append a
+// marker-delimited block to their rc file / $PROFILE; Fish writes a
+// dedicated function file under ~/.config/fish/functions instead, since
+// fish has no single rc file convention for this kind of hook.
+func InstallIntegration(shellName string, opts IntegrationOptions) error {
+	shellName = NormalizeShellName(shellName)
+
+	switch shellName {
+	case Bash, Zsh, PowerShell:
+		path, err := rcFilePath(shellName)
+		if err != nil {
+			return err
+		}
+		return writeMarkedBlock(path, printSnippet, shellName, opts)
+	case Fish:
+		return installFishIntegration(opts)
+	default:
+		return fmt.Errorf("unsupported shell for integration: %s", shellName)
+	}
+}
+
+// UninstallIntegration removes a previously installed integration block (or,
+// for Fish, the generated function file).
+func UninstallIntegration(shellName string) error {
+	shellName = NormalizeShellName(shellName)
+
+	switch shellName {
+	case Bash, Zsh, PowerShell:
+		path, err := rcFilePath(shellName)
+		if err != nil {
+			return err
+		}
+		return removeMarkedBlock(path)
+	case Fish:
+		return uninstallFishIntegration()
+	default:
+		return fmt.Errorf("unsupported shell for integration: %s", shellName)
+	}
+}
+
+// PrintIntegrationSnippet writes the integration snippet for shellName to w,
+// without any marker lines, so it can be sourced directly, e.g.
+// `eval "$(uhh shell hook bash)"`.
+func PrintIntegrationSnippet(shellName string, w io.Writer) error {
+	return printSnippet(shellName, IntegrationOptions{}, w)
+}
+
+func printSnippet(shellName string, opts IntegrationOptions, w io.Writer) error {
+	shellName = NormalizeShellName(shellName)
+
+	switch shellName {
+	case Bash:
+		fmt.Fprint(w, bashZshSnippet("bash", opts))
+	case Zsh:
+		fmt.Fprint(w, bashZshSnippet("zsh", opts))
+	case Fish:
+		fmt.Fprint(w, fishFunctionBody(opts))
+	case PowerShell:
+		fmt.Fprint(w, powershellSnippet(opts))
+	default:
+		return fmt.Errorf("unsupported shell for integration: %s", shellName)
+	}
+	return nil
+}
+
+func rcFilePath(shellName string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	switch shellName {
+	case Bash:
+		return filepath.Join(home, ".bashrc"), nil
+	case Zsh:
+		return filepath.Join(home, ".zshrc"), nil
+	case PowerShell:
+		return powershellProfilePath(home)
+	default:
+		return "", fmt.Errorf("%s has no single rc file", shellName)
+	}
+}
+
+func powershellProfilePath(home string) (string, error) {
+	// $PROFILE for PowerShell 7+ on all platforms; Windows PowerShell 5.1
+	// uses "WindowsPowerShell" instead of "PowerShell", but this default is
+	// close enough for a generated hook and is overridable via --rc-file.
+	return filepath.Join(home, "Documents", "PowerShell", "Microsoft.PowerShell_profile.ps1"), nil
+}
+
+func bashZshSnippet(shellName string, opts IntegrationOptions) string {
+	bin := opts.binary()
+	hotkey := opts.Hotkey
+	if hotkey == "" {
+		hotkey = `\C-x\C-h`
+	}
+
+	handlerName := "command_not_found_handle"
+	if shellName == "zsh" {
+		handlerName = "command_not_found_handler"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# uhh completion\n")
+	fmt.Fprintf(&sb, "source <(%s completion %s)\n\n", bin, shellName)
+	fmt.Fprintf(&sb, "%s() {\n", handlerName)
+	fmt.Fprintf(&sb, "    %s --suggest \"$@\"\n", bin)
+	fmt.Fprintf(&sb, "}\n\n")
+	fmt.Fprintf(&sb, "__uhh_insert_last_command() {\n")
+	fmt.Fprintf(&sb, "    READLINE_LINE=$(%s --last)\n", bin)
+	fmt.Fprintf(&sb, "    READLINE_POINT=${#READLINE_LINE}\n")
+	fmt.Fprintf(&sb, "}\n")
+	fmt.Fprintf(&sb, "bind -x '\"%s\": __uhh_insert_last_command'\n", hotkey)
+
+	return sb.String()
+}
+
+func fishFunctionBody(opts IntegrationOptions) string {
+	bin := opts.binary()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "function __uhh_not_found --on-event fish_command_not_found\n")
+	fmt.Fprintf(&sb, "    %s --suggest $argv\n", bin)
+	fmt.Fprintf(&sb, "end\n")
+	return sb.String()
+}
+
+func installFishIntegration(opts IntegrationOptions) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(home, ".config", "fish", "functions")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "__uhh_not_found.fish"), []byte(fishFunctionBody(opts)), 0644)
+}
+
+func uninstallFishIntegration() error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(home, ".config", "fish", "functions", "__uhh_not_found.fish")
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func powershellSnippet(opts IntegrationOptions) string {
+	bin := opts.binary()
+	hotkey := opts.Hotkey
+	if hotkey == "" {
+		hotkey = "Ctrl+x,Ctrl+h"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Register-ArgumentCompleter -Native -CommandName %s -ScriptBlock {\n", bin)
+	fmt.Fprintf(&sb, "    param($wordToComplete, $commandAst, $cursorPosition)\n")
+	fmt.Fprintf(&sb, "    & %s completion powershell --word $wordToComplete | ForEach-Object {\n", bin)
+	fmt.Fprintf(&sb, "        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)\n")
+	fmt.Fprintf(&sb, "    }\n")
+	fmt.Fprintf(&sb, "}\n\n")
+	fmt.Fprintf(&sb, "Set-PSReadLineKeyHandler -Chord '%s' -ScriptBlock {\n", hotkey)
+	fmt.Fprintf(&sb, "    $last = & %s --last\n", bin)
+	fmt.Fprintf(&sb, "    [Microsoft.PowerShell.PSConsoleReadLine]::RevertLine()\n")
+	fmt.Fprintf(&sb, "    [Microsoft.PowerShell.PSConsoleReadLine]::Insert($last)\n")
+	fmt.Fprintf(&sb, "}\n")
+	return sb.String()
+}
+
+// writeMarkedBlock appends (or replaces, if already present) a
+// marker-delimited block generated by snippetFn to path, creating the file
+// and its parent directory if necessary.
+func writeMarkedBlock(path string, snippetFn func(string, IntegrationOptions, io.Writer) error, shellName string, opts IntegrationOptions) error {
+	var body bytes.Buffer
+	if err := snippetFn(shellName, opts, &body); err != nil {
+		return err
+	}
+
+	block := markerStart + "\n" + body.String() + markerEnd + "\n"
+
+	existing, err := readOrEmpty(path)
+	if err != nil {
+		return err
+	}
+
+	updated, replaced := replaceMarkedBlock(existing, block)
+	if !replaced {
+		if len(updated) > 0 && !strings.HasSuffix(updated, "\n") {
+			updated += "\n"
+		}
+		updated += block
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(updated), 0644)
+}
+
+// removeMarkedBlock strips a previously installed marker-delimited block
+// from path, leaving everything else untouched. It's a no-op if path
+// doesn't exist or has no block.
+func removeMarkedBlock(path string) error {
+	existing, err := readOrEmpty(path)
+	if err != nil {
+		return err
+	}
+	if existing == "" {
+		return nil
+	}
+
+	updated, replaced := replaceMarkedBlock(existing, "")
+	if !replaced {
+		return nil
+	}
+	return os.WriteFile(path, []byte(updated), 0644)
+}
+
+// replaceMarkedBlock returns content with the first markerStart..markerEnd
+// block (inclusive) replaced by replacement. replaced reports whether a
+// block was found.
+func replaceMarkedBlock(content, replacement string) (result string, replaced bool) {
+	lines := strings.Split(content, "\n")
+	startIdx, endIdx := -1, -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == markerStart {
+			startIdx = i
+		} else if strings.TrimSpace(line) == markerEnd && startIdx != -1 {
+			endIdx = i
+			break
+		}
+	}
+
+	if startIdx == -1 || endIdx == -1 {
+		return content, false
+	}
+
+	before := strings.Join(lines[:startIdx], "\n")
+	after := strings.Join(lines[endIdx+1:], "\n")
+
+	var sb strings.Builder
+	sb.WriteString(before)
+	if before != "" {
+		sb.WriteString("\n")
+	}
+	sb.WriteString(replacement)
+	sb.WriteString(after)
+
+	return sb.String(), true
+}
+
+func readOrEmpty(path string) (string, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var sb strings.Builder
+	scanner := bufio.NewScanner(f)
+	first := true
+	for scanner.Scan() {
+		if !first {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(scanner.Text())
+		first = false
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
@@ -3,9 +3,11 @@ package shell
 import (
 	"fmt"
 	"os"
-	"path/filepath"
-	"regexp"
+	"runtime"
 	"strings"
+	"text/template"
+
+	shellcontext "uhh/internal/shell/context"
 )
 
 const promptTemplate = `<instruction>
@@ -43,87 +45,61 @@ Always prefer using tools over just providing text responses when actions are ne
 Be careful with destructive operations - confirm with the user if uncertain.`, shell)
 }
 
-// buildFileContext builds the file context section from referenced files
+// buildFileContext resolves real files referenced in query against a
+// workspace index (honoring .gitignore, matched via exact basename or fuzzy
+// token matching) and renders the ones that fit within maxTokens as a
+// <file_contexts> block. This replaces the old regex-based extractor, which
+// matched on bare "word.word" patterns and produced false positives like
+// "v1.0" or "foo.bar" in prose.
 func buildFileContext(query string, maxTokens int) string {
-	files := ExtractFileReferences(query)
-	if len(files) == 0 {
+	cwd, err := os.Getwd()
+	if err != nil {
 		return ""
 	}
 
-	var contextFiles []string
-	var contextFileNames []string
-
-	for _, file := range files {
-		if small, err := IsSmallFile(file, maxTokens); err == nil && small {
-			content, err := os.ReadFile(file)
-			if err == nil {
-				contextFiles = append(contextFiles, string(content))
-				contextFileNames = append(contextFileNames, file)
-			}
-		}
-	}
-
-	if len(contextFiles) == 0 {
+	idx, err := shellcontext.Build(cwd)
+	if err != nil {
 		return ""
 	}
 
-	var sb strings.Builder
-	sb.WriteString("<file_contexts>\n")
-	for i, content := range contextFiles {
-		sb.WriteString(fmt.Sprintf("<file name='%s'>\n", filepath.Base(contextFileNames[i])))
-		sb.WriteString(content)
-		sb.WriteString("\n</file>\n")
-	}
-	sb.WriteString("</file_contexts>\n")
+	return shellcontext.Render(idx.SelectFileContexts(query, maxTokens))
+}
 
-	return sb.String()
+// PromptTemplateData is the placeholder set available to a profile's
+// SystemPromptTemplate (see config.Profile).
+type PromptTemplateData struct {
+	Shell       string
+	CWD         string
+	OS          string
+	FileContext string
 }
 
-// ExtractFileReferences finds potential file paths in the user prompt
-func ExtractFileReferences(text string) []string {
-	var files []string
-	seen := make(map[string]bool)
-
-	patterns := []string{
-		// Files with extensions
-		`\b[\w\-\.\/\\]+\.[a-zA-Z0-9]+\b`,
-		// Quoted file paths
-		`["']([^"']+\.[a-zA-Z0-9]+)["']`,
-		// Common config files
-		`\b(package\.json|go\.mod|go\.sum|Dockerfile|Makefile|README\.md|\.gitignore)\b`,
+// RenderSystemPromptTemplate renders tmpl, a Go text/template string from a
+// profile's SystemPromptTemplate, with Shell/CWD/OS/FileContext placeholders
+// filled in. It's used in place of BuildAgentSystemPrompt's fixed prompt
+// when a profile defines one, e.g. a "bash-strict" profile with a hardened
+// system prompt.
+func RenderSystemPromptTemplate(tmpl, query, shellName string, appendContext bool, maxTokens int) (string, error) {
+	data := PromptTemplateData{
+		Shell: shellName,
+		OS:    runtime.GOOS,
 	}
-
-	for _, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
-		matches := re.FindAllString(text, -1)
-		for _, match := range matches {
-			match = strings.Trim(match, `"'`)
-			if !seen[match] {
-				files = append(files, match)
-				seen[match] = true
-			}
-		}
+	if cwd, err := os.Getwd(); err == nil {
+		data.CWD = cwd
 	}
-
-	return files
-}
-
-// IsSmallFile checks if a file exists and is small enough to include
-func IsSmallFile(filePath string, maxTokens int) (bool, error) {
-	// Convert relative paths to absolute
-	if !filepath.IsAbs(filePath) {
-		abs, err := filepath.Abs(filePath)
-		if err == nil {
-			filePath = abs
-		}
+	if appendContext && query != "" {
+		data.FileContext = buildFileContext(query, maxTokens)
 	}
 
-	info, err := os.Stat(filePath)
+	t, err := template.New("system-prompt").Parse(tmpl)
 	if err != nil {
-		return false, err
+		return "", fmt.Errorf("invalid system prompt template: %w", err)
+	}
+
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render system prompt template: %w", err)
 	}
 
-	// Rough approximation: 1 token ~ 4 characters
-	maxBytes := int64(maxTokens * 4)
-	return info.Size() <= maxBytes, nil
+	return sb.String(), nil
 }
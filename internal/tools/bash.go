@@ -1,15 +1,14 @@
 package tools
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"regexp"
-	"runtime"
 	"strings"
 	"time"
+
+	"uhh/internal/config"
 )
 
 const (
@@ -20,6 +19,7 @@ const (
 // BashTool implements a shell command execution tool
 type BashTool struct {
 	Timeout time.Duration
+	Sandbox Sandbox
 }
 
 // BashInput represents the input for the bash tool
@@ -27,10 +27,21 @@ type BashInput struct {
 	Command string `json:"command"`
 }
 
-// NewBashTool creates a new bash tool with default settings
+// NewBashTool creates a new bash tool with default settings, executing
+// commands directly on the host.
 func NewBashTool() *BashTool {
 	return &BashTool{
 		Timeout: defaultTimeout,
+		Sandbox: &HostSandbox{},
+	}
+}
+
+// NewBashToolWithConfig creates a bash tool whose execution is isolated
+// according to cfg.Sandbox (see internal/tools.NewSandbox).
+func NewBashToolWithConfig(cfg config.ShellSettings) *BashTool {
+	return &BashTool{
+		Timeout: defaultTimeout,
+		Sandbox: NewSandbox(cfg),
 	}
 }
 
@@ -82,26 +93,13 @@ func (t *BashTool) Execute(ctx context.Context, input Input) (Output, error) {
 	execCtx, cancel := context.WithTimeout(ctx, t.Timeout)
 	defer cancel()
 
-	// Determine shell and execute
-	var cmd *exec.Cmd
-	if runtime.GOOS == "windows" {
-		cmd = exec.CommandContext(execCtx, "cmd", "/C", bashInput.Command)
-	} else {
-		cmd = exec.CommandContext(execCtx, "sh", "-c", bashInput.Command)
-	}
-
-	// Set working directory if specified
-	if input.WorkingDir != "" {
-		cmd.Dir = input.WorkingDir
+	sandbox := t.Sandbox
+	if sandbox == nil {
+		sandbox = &HostSandbox{}
 	}
 
-	// Capture output
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	// Execute
-	err := cmd.Run()
+	// Execute via the configured sandbox (host, container, or firejail)
+	stdout, stderr, err := sandbox.Run(execCtx, bashInput.Command, input.WorkingDir, nil)
 
 	// Check for timeout
 	if execCtx.Err() == context.DeadlineExceeded {
@@ -109,12 +107,12 @@ func (t *BashTool) Execute(ctx context.Context, input Input) (Output, error) {
 	}
 
 	// Build result
-	result := stdout.String()
-	if stderr.Len() > 0 {
+	result := string(stdout)
+	if len(stderr) > 0 {
 		if result != "" {
 			result += "\n"
 		}
-		result += "[stderr]\n" + stderr.String()
+		result += "[stderr]\n" + string(stderr)
 	}
 
 	// Truncate if too long
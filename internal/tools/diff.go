@@ -0,0 +1,195 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContextLines is how many unchanged lines surround each hunk of
+// changes, matching the conventional `diff -u` default.
+const diffContextLines = 3
+
+// diffOpKind tags one line of an line-level diff.
+type diffOpKind int
+
+const (
+	diffEqual diffOpKind = iota
+	diffAdd
+	diffRemove
+)
+
+type diffOp struct {
+	kind diffOpKind
+	line string
+}
+
+// diffLines computes a line-level diff between oldLines and newLines using
+// a longest-common-subsequence, so equal lines are preserved as context and
+// the rest show up as additions/removals.
+func diffLines(oldLines, newLines []string) []diffOp {
+	n, m := len(oldLines), len(newLines)
+
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case oldLines[i] == newLines[j]:
+			ops = append(ops, diffOp{diffEqual, oldLines[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{diffRemove, oldLines[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{diffAdd, newLines[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{diffRemove, oldLines[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{diffAdd, newLines[j]})
+	}
+	return ops
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimRight(s, "\n"), "\n")
+}
+
+// diffStats summarizes a diff's line counts, used for GetWriteDescription
+// and the stats line tui.ConfirmFileWrite renders alongside the diff.
+type diffStats struct {
+	Added   int
+	Removed int
+}
+
+// String renders stats as "+N/-M lines".
+func (s diffStats) String() string {
+	return fmt.Sprintf("+%d/-%d lines", s.Added, s.Removed)
+}
+
+// unifiedDiff renders a standard `diff -u`-style patch between oldContent
+// and newContent, labeled with path, along with its line-count stats. It
+// returns ("", zero stats) when the two are identical.
+func unifiedDiff(path, oldContent, newContent string) (string, diffStats) {
+	ops := diffLines(splitLines(oldContent), splitLines(newContent))
+
+	type lineRec struct {
+		kind  diffOpKind
+		text  string
+		oldN  int
+		newN  int
+	}
+
+	recs := make([]lineRec, 0, len(ops))
+	oldN, newN := 0, 0
+	var changed []int
+	for _, op := range ops {
+		switch op.kind {
+		case diffEqual:
+			oldN++
+			newN++
+		case diffRemove:
+			oldN++
+		case diffAdd:
+			newN++
+		}
+		if op.kind != diffEqual {
+			changed = append(changed, len(recs))
+		}
+		recs = append(recs, lineRec{op.kind, op.line, oldN, newN})
+	}
+
+	var stats diffStats
+	if len(changed) == 0 {
+		return "", stats
+	}
+
+	// Group nearby changes into hunks, the same way `diff -u` merges runs
+	// separated by less than 2*context unchanged lines.
+	type hunk struct{ start, end int }
+	hunks := []hunk{{changed[0], changed[0]}}
+	for _, idx := range changed[1:] {
+		last := &hunks[len(hunks)-1]
+		if idx-last.end <= diffContextLines*2 {
+			last.end = idx
+		} else {
+			hunks = append(hunks, hunk{idx, idx})
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", path, path)
+
+	for _, h := range hunks {
+		from := h.start - diffContextLines
+		if from < 0 {
+			from = 0
+		}
+		to := h.end + diffContextLines
+		if to >= len(recs) {
+			to = len(recs) - 1
+		}
+
+		// Start from the lines consumed on each side before the hunk; the
+		// range header then names the hunk's first line on that side, or
+		// the line before it when that side of the hunk is empty.
+		oldStart, newStart := 0, 0
+		if from > 0 {
+			oldStart, newStart = recs[from-1].oldN, recs[from-1].newN
+		}
+
+		var oldCount, newCount int
+		var body strings.Builder
+		for i := from; i <= to; i++ {
+			r := recs[i]
+			switch r.kind {
+			case diffEqual:
+				oldCount++
+				newCount++
+				body.WriteString(" " + r.text + "\n")
+			case diffRemove:
+				oldCount++
+				stats.Removed++
+				body.WriteString("-" + r.text + "\n")
+			case diffAdd:
+				newCount++
+				stats.Added++
+				body.WriteString("+" + r.text + "\n")
+			}
+		}
+
+		if oldCount > 0 {
+			oldStart++
+		}
+		if newCount > 0 {
+			newStart++
+		}
+
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		b.WriteString(body.String())
+	}
+
+	return b.String(), stats
+}
@@ -0,0 +1,194 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// defaultExternalTimeout bounds a single external tool invocation when its
+// manifest doesn't declare one.
+const defaultExternalTimeout = 30 * time.Second
+
+// ExternalToolManifest describes a tool plugin discovered under ToolsDir():
+// an executable that reads a JSON-encoded Input.Raw on stdin and writes its
+// result to stdout, following the same "plugin as subprocess" convention as
+// provider.PluginManifest. This lets users add tools like kubectl, docker,
+// or jq without patching Go code.
+type ExternalToolManifest struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Parameters  map[string]interface{} `json:"parameters"`
+	Command     string                 `json:"command"`
+	Args        []string               `json:"args,omitempty"`
+	Env         map[string]string      `json:"env,omitempty"`
+	TimeoutSecs int                    `json:"timeout_seconds,omitempty"`
+	Safety      string                 `json:"safety,omitempty"` // "safe", "moderate", or "dangerous"; defaults to "moderate"
+}
+
+// ToolsDir returns ~/.uhh/tools, scanned by LoadExternal for tool plugin
+// manifests at startup.
+func ToolsDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "./.uhh/tools"
+	}
+	return filepath.Join(home, ".uhh", "tools")
+}
+
+// LoadExternal scans ToolsDir() for subdirectories containing a
+// manifest.json and registers each as a Tool. Errors are collected rather
+// than returned eagerly so one broken plugin doesn't stop the built-ins (or
+// other plugins) from loading.
+func LoadExternal(registry *Registry) []error {
+	dir := ToolsDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return []error{fmt.Errorf("tools: failed to read tools dir %s: %w", dir, err)}
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		manifestPath := filepath.Join(dir, entry.Name(), "manifest.json")
+		if err := RegisterExternal(registry, manifestPath); err != nil {
+			errs = append(errs, fmt.Errorf("tool[%s]: %w", entry.Name(), err))
+		}
+	}
+
+	return errs
+}
+
+// RegisterExternal reads the manifest at manifestPath and registers the tool
+// it describes into registry.
+func RegisterExternal(registry *Registry, manifestPath string) error {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var manifest ExternalToolManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("invalid manifest: %w", err)
+	}
+	if manifest.Name == "" || manifest.Command == "" {
+		return fmt.Errorf("manifest must declare name and command")
+	}
+
+	timeout := defaultExternalTimeout
+	if manifest.TimeoutSecs > 0 {
+		timeout = time.Duration(manifest.TimeoutSecs) * time.Second
+	}
+
+	safety := SafetyLevelModerate
+	switch manifest.Safety {
+	case "safe":
+		safety = SafetyLevelSafe
+	case "dangerous":
+		safety = SafetyLevelDangerous
+	}
+
+	parameters := manifest.Parameters
+	if parameters == nil {
+		parameters = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	}
+
+	registry.Register(&externalTool{
+		name:        manifest.Name,
+		description: manifest.Description,
+		parameters:  parameters,
+		command:     manifest.Command,
+		args:        manifest.Args,
+		env:         manifest.Env,
+		dir:         filepath.Dir(manifestPath),
+		timeout:     timeout,
+		safety:      safety,
+	})
+
+	return nil
+}
+
+// externalTool adapts a subprocess described by an ExternalToolManifest to
+// the Tool interface, passing Input.Raw as JSON over stdin and returning
+// stdout (with stderr appended on failure) as the result.
+type externalTool struct {
+	name        string
+	description string
+	parameters  map[string]interface{}
+	command     string
+	args        []string
+	env         map[string]string
+	dir         string
+	timeout     time.Duration
+	safety      SafetyLevel
+}
+
+func (t *externalTool) Name() string { return t.name }
+
+func (t *externalTool) Description() string { return t.description }
+
+func (t *externalTool) Parameters() map[string]interface{} { return t.parameters }
+
+func (t *externalTool) RequiresConfirmation() bool {
+	return t.safety != SafetyLevelSafe
+}
+
+func (t *externalTool) SafetyLevel() SafetyLevel { return t.safety }
+
+func (t *externalTool) Execute(ctx context.Context, input Input) (Output, error) {
+	execCtx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	raw := input.Raw
+	if raw == "" {
+		raw = "{}"
+	}
+
+	cmd := exec.CommandContext(execCtx, t.command, t.args...)
+	cmd.Dir = t.dir
+	if input.WorkingDir != "" {
+		cmd.Dir = input.WorkingDir
+	}
+	cmd.Env = os.Environ()
+	for k, v := range t.env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	cmd.Stdin = bytes.NewReader([]byte(raw))
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+
+	if execCtx.Err() == context.DeadlineExceeded {
+		return NewErrorOutputString(fmt.Sprintf("tool %s timed out after %v", t.name, t.timeout)), nil
+	}
+
+	if err != nil {
+		result := stdout.String()
+		if stderr.Len() > 0 {
+			if result != "" {
+				result += "\n"
+			}
+			result += "[stderr]\n" + stderr.String()
+		}
+		return Output{Success: false, Result: result, Error: err.Error()}, nil
+	}
+
+	result := stdout.String()
+	if result == "" {
+		result = "(tool completed with no output)"
+	}
+	return NewOutput(result), nil
+}
@@ -1,17 +1,23 @@
 package tools
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
 const (
-	maxFileSize  = 100 * 1024 // 100KB max file size
-	maxLineCount = 1000       // Max lines to read
+	maxFileSize     = 100 * 1024 // 100KB default max file size
+	maxLineCount    = 1000       // Default max lines to read
+	maxGlobTotal    = 500 * 1024 // Total byte cap across all matches in a glob read
+	binarySniffSize = 512        // Bytes scanned to detect binary content
 )
 
 // FileReadTool implements a file reading tool
@@ -19,7 +25,27 @@ type FileReadTool struct{}
 
 // FileReadInput represents the input for the file read tool
 type FileReadInput struct {
-	Path string `json:"path"`
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+	MaxBytes  int    `json:"max_bytes,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+}
+
+// globMatch is one entry of a glob/pattern read
+type globMatch struct {
+	Path    string `json:"path"`
+	Content string `json:"content,omitempty"`
+	Binary  bool   `json:"binary,omitempty"`
+	Size    int64  `json:"size,omitempty"`
+	SHA256  string `json:"sha256,omitempty"`
+}
+
+// binaryResult is returned for a single binary file instead of its raw bytes
+type binaryResult struct {
+	Binary bool   `json:"binary"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
 }
 
 // NewFileReadTool creates a new file read tool
@@ -34,7 +60,9 @@ func (t *FileReadTool) Name() string {
 
 // Description returns the tool description
 func (t *FileReadTool) Description() string {
-	return "Read the contents of a file. Input should be a JSON object with a 'path' field containing the file path."
+	return "Read the contents of a file, a line range of a file, or files matching a glob pattern. " +
+		"Input should be a JSON object with a 'path' field, or with 'pattern' to read multiple files. " +
+		"Optional fields: 'start_line'/'end_line' (1-indexed), 'max_bytes'."
 }
 
 // Parameters returns the JSON schema for the tool parameters
@@ -46,8 +74,24 @@ func (t *FileReadTool) Parameters() map[string]interface{} {
 				"type":        "string",
 				"description": "The path to the file to read",
 			},
+			"start_line": map[string]interface{}{
+				"type":        "integer",
+				"description": "First line to read, 1-indexed (requires 'path')",
+			},
+			"end_line": map[string]interface{}{
+				"type":        "integer",
+				"description": "Last line to read, 1-indexed, inclusive (requires 'path')",
+			},
+			"max_bytes": map[string]interface{}{
+				"type":        "integer",
+				"description": "Override the default max byte cap for this read",
+			},
+			"pattern": map[string]interface{}{
+				"type":        "string",
+				"description": "Glob pattern (e.g. '*.go') to read multiple files under the working directory, honoring .gitignore",
+			},
 		},
-		"required": []string{"path"},
+		"required": []string{},
 	}
 }
 
@@ -56,36 +100,27 @@ func (t *FileReadTool) Execute(ctx context.Context, input Input) (Output, error)
 	var readInput FileReadInput
 
 	if err := json.Unmarshal([]byte(input.Raw), &readInput); err != nil {
-		// Try treating raw input as path
+		// Try treating raw input as a plain path, preserving existing agent behavior
 		readInput.Path = strings.TrimSpace(input.Raw)
 	}
 
+	if readInput.Pattern != "" {
+		return t.executeGlob(readInput, input.WorkingDir)
+	}
+
 	if readInput.Path == "" {
 		return NewErrorOutputString("path cannot be empty"), nil
 	}
 
-	// Resolve path
-	path := readInput.Path
-	if !filepath.IsAbs(path) {
-		if input.WorkingDir != "" {
-			path = filepath.Join(input.WorkingDir, path)
-		} else {
-			absPath, err := filepath.Abs(path)
-			if err == nil {
-				path = absPath
-			}
-		}
-	}
+	return t.executeSingle(readInput, input.WorkingDir)
+}
 
-	// Security check - prevent path traversal
-	if strings.Contains(path, "..") {
-		cleanPath := filepath.Clean(path)
-		if strings.HasPrefix(cleanPath, "..") {
-			return NewErrorOutputString("path traversal not allowed"), nil
-		}
+func (t *FileReadTool) executeSingle(readInput FileReadInput, workingDir string) (Output, error) {
+	path, err := resolveReadPath(readInput.Path, workingDir)
+	if err != nil {
+		return NewErrorOutputString(err.Error()), nil
 	}
 
-	// Check file exists
 	info, err := os.Stat(path)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -94,22 +129,39 @@ func (t *FileReadTool) Execute(ctx context.Context, input Input) (Output, error)
 		return NewErrorOutput(err), nil
 	}
 
-	// Check if it's a directory
 	if info.IsDir() {
 		return NewErrorOutputString(fmt.Sprintf("%s is a directory, not a file", readInput.Path)), nil
 	}
 
-	// Check file size
-	if info.Size() > maxFileSize {
-		return NewErrorOutputString(fmt.Sprintf("file too large (%d bytes, max %d bytes)", info.Size(), maxFileSize)), nil
+	maxBytes := maxFileSize
+	if readInput.MaxBytes > 0 {
+		maxBytes = readInput.MaxBytes
+	}
+
+	if binary, result, err := sniffBinary(path, info.Size()); err != nil {
+		return NewErrorOutput(err), nil
+	} else if binary {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return NewErrorOutput(err), nil
+		}
+		return NewOutput(string(data)), nil
+	}
+
+	if info.Size() > int64(maxBytes) && readInput.StartLine == 0 && readInput.EndLine == 0 {
+		return NewErrorOutputString(fmt.Sprintf("file too large (%d bytes, max %d bytes)", info.Size(), maxBytes)), nil
 	}
 
-	// Read file
 	content, err := os.ReadFile(path)
 	if err != nil {
 		return NewErrorOutput(err), nil
 	}
 
+	if readInput.StartLine > 0 || readInput.EndLine > 0 {
+		content = []byte(sliceLines(string(content), readInput.StartLine, readInput.EndLine))
+		return NewOutput(string(content)), nil
+	}
+
 	// Truncate by line count if needed
 	lines := strings.Split(string(content), "\n")
 	if len(lines) > maxLineCount {
@@ -119,6 +171,209 @@ func (t *FileReadTool) Execute(ctx context.Context, input Input) (Output, error)
 	return NewOutput(string(content)), nil
 }
 
+func (t *FileReadTool) executeGlob(readInput FileReadInput, workingDir string) (Output, error) {
+	root := workingDir
+	if root == "" {
+		root = "."
+	}
+
+	ignore := loadGitignore(root)
+
+	var matches []globMatch
+	var totalBytes int64
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // best-effort: skip unreadable entries
+		}
+
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			rel = path
+		}
+
+		if info.IsDir() {
+			if rel != "." && ignore.matches(rel, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if ignore.matches(rel, false) {
+			return nil
+		}
+
+		matched, matchErr := filepath.Match(readInput.Pattern, filepath.Base(path))
+		if matchErr != nil {
+			return matchErr
+		}
+		if !matched {
+			// Also allow matching against the relative path for patterns like "src/**/*.go"-style prefixes
+			if ok, _ := filepath.Match(readInput.Pattern, rel); !ok {
+				return nil
+			}
+		}
+
+		if totalBytes >= maxGlobTotal {
+			return nil
+		}
+
+		if binary, binResult, err := sniffBinary(path, info.Size()); err == nil && binary {
+			matches = append(matches, globMatch{Path: rel, Binary: true, Size: binResult.Size, SHA256: binResult.SHA256})
+			return nil
+		}
+
+		remaining := maxGlobTotal - totalBytes
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		if int64(len(content)) > remaining {
+			content = content[:remaining]
+		}
+		totalBytes += int64(len(content))
+
+		matches = append(matches, globMatch{Path: rel, Content: string(content)})
+		return nil
+	})
+	if err != nil {
+		return NewErrorOutput(err), nil
+	}
+
+	data, err := json.Marshal(matches)
+	if err != nil {
+		return NewErrorOutput(err), nil
+	}
+
+	return NewOutput(string(data)), nil
+}
+
+// resolveReadPath resolves a user-supplied path against the working
+// directory, joining (and thereby cleaning) a relative path and leaving an
+// absolute one as-is. It does not confine the result to workingDir - unlike
+// FileWriteTool, FileReadTool is SafetyLevelSafe and doesn't require
+// confirmation, and reading outside the working directory (e.g. an
+// absolute path to a config file elsewhere on disk) is routine, not a
+// traversal attack.
+func resolveReadPath(rawPath, workingDir string) (string, error) {
+	path := rawPath
+	if !filepath.IsAbs(path) {
+		if workingDir != "" {
+			path = filepath.Join(workingDir, path)
+		} else {
+			absPath, err := filepath.Abs(path)
+			if err == nil {
+				path = absPath
+			}
+		}
+	}
+
+	return path, nil
+}
+
+// sniffBinary scans the first bytes of a file for NUL bytes to detect
+// binary content, returning a structured summary instead of raw bytes.
+func sniffBinary(path string, size int64) (bool, binaryResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, binaryResult{}, err
+	}
+	defer f.Close()
+
+	head := make([]byte, binarySniffSize)
+	n, _ := f.Read(head)
+	if !bytes.Contains(head[:n], []byte{0}) {
+		return false, binaryResult{}, nil
+	}
+
+	hash := sha256.New()
+	if _, err := f.Seek(0, 0); err != nil {
+		return false, binaryResult{}, err
+	}
+	if _, err := io.Copy(hash, f); err != nil {
+		return false, binaryResult{}, err
+	}
+
+	return true, binaryResult{
+		Binary: true,
+		Size:   size,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// sliceLines returns the inclusive 1-indexed line range [start, end] of content.
+// A zero start/end means "from the beginning"/"to the end" respectively.
+func sliceLines(content string, start, end int) string {
+	lines := strings.Split(content, "\n")
+	if start <= 0 {
+		start = 1
+	}
+	if end <= 0 || end > len(lines) {
+		end = len(lines)
+	}
+	if start > len(lines) {
+		return ""
+	}
+	return strings.Join(lines[start-1:end], "\n")
+}
+
+// gitignoreMatcher is a small, best-effort .gitignore pattern matcher.
+// It supports plain glob patterns and directory-only patterns (trailing '/'),
+// which covers the common cases without pulling in a full gitignore parser.
+type gitignoreMatcher struct {
+	patterns    []string
+	dirPatterns []string
+}
+
+func loadGitignore(root string) *gitignoreMatcher {
+	m := &gitignoreMatcher{}
+
+	data, err := os.ReadFile(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		return m
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasSuffix(line, "/") {
+			m.dirPatterns = append(m.dirPatterns, strings.TrimSuffix(line, "/"))
+		} else {
+			m.patterns = append(m.patterns, line)
+		}
+	}
+
+	// Always skip the .git directory itself
+	m.dirPatterns = append(m.dirPatterns, ".git")
+
+	return m
+}
+
+func (m *gitignoreMatcher) matches(relPath string, isDir bool) bool {
+	base := filepath.Base(relPath)
+
+	if isDir {
+		for _, p := range m.dirPatterns {
+			if ok, _ := filepath.Match(p, base); ok {
+				return true
+			}
+		}
+	}
+
+	for _, p := range m.patterns {
+		if ok, _ := filepath.Match(p, base); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(p, relPath); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
 // RequiresConfirmation returns false as reading is safe
 func (t *FileReadTool) RequiresConfirmation() bool {
 	return false
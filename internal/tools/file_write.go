@@ -2,15 +2,21 @@ package tools
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"uhh/internal/config"
+	"uhh/internal/filebackup"
 )
 
 // FileWriteTool implements a file writing tool
-type FileWriteTool struct{}
+type FileWriteTool struct {
+	Sandbox Sandbox
+}
 
 // FileWriteInput represents the input for the file write tool
 type FileWriteInput struct {
@@ -19,9 +25,19 @@ type FileWriteInput struct {
 	Append  bool   `json:"append,omitempty"`
 }
 
-// NewFileWriteTool creates a new file write tool
+// NewFileWriteTool creates a new file write tool that writes directly to
+// the host filesystem.
 func NewFileWriteTool() *FileWriteTool {
-	return &FileWriteTool{}
+	return &FileWriteTool{Sandbox: &HostSandbox{}}
+}
+
+// NewFileWriteToolWithConfig creates a file write tool that, when
+// cfg.Sandbox selects a non-host sandbox, confines writes to the bind-mounted
+// working directory that the same sandbox gives BashTool (see
+// internal/tools.NewSandbox) - so writes land inside the container's mount
+// and are visible on the host only via that mount.
+func NewFileWriteToolWithConfig(cfg config.ShellSettings) *FileWriteTool {
+	return &FileWriteTool{Sandbox: NewSandbox(cfg)}
 }
 
 // Name returns the tool name
@@ -56,6 +72,71 @@ func (t *FileWriteTool) Parameters() map[string]interface{} {
 	}
 }
 
+// resolvePath resolves writeInput.Path to an absolute path, confining it to
+// workingDir when a non-host sandbox is active (see sandboxConfine) or
+// applying the looser host path-traversal check otherwise.
+func (t *FileWriteTool) resolvePath(rawPath, workingDir string) (string, error) {
+	sandbox := t.Sandbox
+	if sandbox == nil {
+		sandbox = &HostSandbox{}
+	}
+
+	if !sandbox.Host() {
+		// A non-host sandbox only ever exposes workingDir back to the host
+		// (via its bind mount), so any write must resolve inside it.
+		return sandboxConfine(workingDir, rawPath)
+	}
+
+	path := rawPath
+	if !filepath.IsAbs(path) {
+		if workingDir != "" {
+			path = filepath.Join(workingDir, path)
+		} else if absPath, err := filepath.Abs(path); err == nil {
+			path = absPath
+		}
+	}
+
+	if strings.Contains(path, "..") {
+		cleanPath := filepath.Clean(path)
+		if strings.HasPrefix(cleanPath, "..") {
+			return "", fmt.Errorf("path traversal not allowed")
+		}
+	}
+
+	return path, nil
+}
+
+// Preview resolves the target path and computes a unified diff between its
+// current contents (empty if it doesn't exist yet) and what Execute would
+// write, for tui.ConfirmFileWrite to render before the user approves.
+func (t *FileWriteTool) Preview(input Input) (path, diff, stats string, err error) {
+	var writeInput FileWriteInput
+	if err := json.Unmarshal([]byte(input.Raw), &writeInput); err != nil {
+		return "", "", "", fmt.Errorf("invalid input: %w", err)
+	}
+	if writeInput.Path == "" {
+		return "", "", "", fmt.Errorf("path cannot be empty")
+	}
+
+	resolved, err := t.resolvePath(writeInput.Path, input.WorkingDir)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	oldContent := ""
+	if data, readErr := os.ReadFile(resolved); readErr == nil {
+		oldContent = string(data)
+	}
+
+	newContent := writeInput.Content
+	if writeInput.Append {
+		newContent = oldContent + writeInput.Content
+	}
+
+	diffText, s := unifiedDiff(writeInput.Path, oldContent, newContent)
+	return resolved, diffText, s.String(), nil
+}
+
 // Execute writes content to a file
 func (t *FileWriteTool) Execute(ctx context.Context, input Input) (Output, error) {
 	var writeInput FileWriteInput
@@ -68,56 +149,68 @@ func (t *FileWriteTool) Execute(ctx context.Context, input Input) (Output, error
 		return NewErrorOutputString("path cannot be empty"), nil
 	}
 
-	// Resolve path
-	path := writeInput.Path
-	if !filepath.IsAbs(path) {
-		if input.WorkingDir != "" {
-			path = filepath.Join(input.WorkingDir, path)
-		} else {
-			absPath, err := filepath.Abs(path)
-			if err == nil {
-				path = absPath
-			}
-		}
+	path, err := t.resolvePath(writeInput.Path, input.WorkingDir)
+	if err != nil {
+		return NewErrorOutputString(err.Error()), nil
 	}
 
-	// Security check - prevent path traversal
-	if strings.Contains(path, "..") {
-		cleanPath := filepath.Clean(path)
-		if strings.HasPrefix(cleanPath, "..") {
-			return NewErrorOutputString("path traversal not allowed"), nil
-		}
+	sandbox := t.Sandbox
+	if sandbox == nil {
+		sandbox = &HostSandbox{}
 	}
 
-	// Create parent directories if needed
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return NewErrorOutput(fmt.Errorf("failed to create directory: %w", err)), nil
+	// Create parent directories if needed. In sandbox mode, sandboxWriteFile
+	// below does this itself from inside the sandbox instead, so a
+	// read-only mount rejects it rather than the host silently obliging.
+	if sandbox.Host() {
+		dir := filepath.Dir(path)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return NewErrorOutput(fmt.Errorf("failed to create directory: %w", err)), nil
+		}
 	}
 
-	// Check if file exists for backup
+	// Check if file exists, and load its contents for the diff stats and
+	// the append case
 	var existed bool
-	if _, err := os.Stat(path); err == nil {
+	var oldContent string
+	if data, readErr := os.ReadFile(path); readErr == nil {
 		existed = true
+		oldContent = string(data)
 	}
 
-	// Write file
-	var err error
+	finalContent := writeInput.Content
 	if writeInput.Append {
-		f, openErr := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		if openErr != nil {
-			return NewErrorOutput(openErr), nil
+		finalContent = oldContent + writeInput.Content
+	}
+
+	// Snapshot the previous contents before clobbering them, so `uhh undo`
+	// can restore them later.
+	if existed {
+		if _, err := filebackup.Save(path); err != nil {
+			return NewErrorOutput(fmt.Errorf("failed to back up %s before overwrite: %w", path, err)), nil
 		}
-		_, err = f.WriteString(writeInput.Content)
-		f.Close()
-	} else {
-		err = os.WriteFile(path, []byte(writeInput.Content), 0644)
 	}
 
-	if err != nil {
-		return NewErrorOutput(err), nil
+	if sandbox.Host() {
+		if err := atomicWriteFile(path, []byte(finalContent), 0644); err != nil {
+			return NewErrorOutput(err), nil
+		}
+	} else {
+		// A non-host sandbox's isolation (read-only mount, dropped
+		// capabilities, ...) only applies to what runs inside it, so the
+		// write itself must go through Sandbox.Run instead of os.OpenFile
+		// on the host - otherwise SandboxReadOnly is silently unenforced.
+		rel, err := filepath.Rel(input.WorkingDir, path)
+		if err != nil {
+			return NewErrorOutput(fmt.Errorf("failed to resolve sandboxed write path: %w", err)), nil
+		}
+		if err := sandboxWriteFile(ctx, sandbox, input.WorkingDir, rel, []byte(finalContent)); err != nil {
+			return NewErrorOutput(err), nil
+		}
 	}
 
+	_, stats := unifiedDiff(writeInput.Path, oldContent, finalContent)
+
 	// Build result message
 	action := "created"
 	if existed {
@@ -128,7 +221,69 @@ func (t *FileWriteTool) Execute(ctx context.Context, input Input) (Output, error
 		}
 	}
 
-	return NewOutput(fmt.Sprintf("Successfully %s file: %s (%d bytes)", action, writeInput.Path, len(writeInput.Content))), nil
+	return NewOutput(fmt.Sprintf("Successfully %s file: %s (%s)", action, writeInput.Path, stats.String())), nil
+}
+
+// sandboxWriteFile performs the same atomic-write-then-rename as
+// atomicWriteFile, but by handing a shell script to sandbox.Run instead of
+// calling os.OpenFile on the host - so the write is actually subject to the
+// sandbox's isolation (a read-only container/firejail mount rejects it, the
+// same as it would reject a `bash` tool call writing the file). relPath is
+// resolved relative to workdir, since that's what's bind-mounted in.
+func sandboxWriteFile(ctx context.Context, sandbox Sandbox, workdir, relPath string, content []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(content)
+	script := fmt.Sprintf(
+		`path=%s; dir=$(dirname "$path"); tmp="$path.uhh-tmp-$$"; mkdir -p "$dir" && printf '%%s' %s | base64 -d > "$tmp" && mv "$tmp" "$path"`,
+		shellQuote(relPath), shellQuote(encoded),
+	)
+
+	_, stderr, err := sandbox.Run(ctx, script, workdir, nil)
+	if err != nil {
+		if msg := strings.TrimSpace(string(stderr)); msg != "" {
+			return fmt.Errorf("sandboxed write of %s failed: %s", relPath, msg)
+		}
+		return fmt.Errorf("sandboxed write of %s failed: %w", relPath, err)
+	}
+	return nil
+}
+
+// shellQuote wraps s in single quotes for safe interpolation into a sh -c
+// script, escaping any single quotes it contains.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// atomicWriteFile writes content to a temp file alongside path, fsyncs it,
+// and renames it into place, so a crash or concurrent reader never observes
+// a partially written file.
+func atomicWriteFile(path string, content []byte, perm os.FileMode) error {
+	tmpPath := fmt.Sprintf("%s.uhh-tmp-%d", path, os.Getpid())
+
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
 }
 
 // RequiresConfirmation returns true as writing modifies the filesystem
@@ -141,11 +296,13 @@ func (t *FileWriteTool) SafetyLevel() SafetyLevel {
 	return SafetyLevelModerate
 }
 
-// GetWriteDescription returns a human-readable description of the write operation
-func GetWriteDescription(input FileWriteInput) string {
+// GetWriteDescription returns a human-readable description of the write
+// operation, including the given diff stats summary (e.g. "+3/-1 lines",
+// as returned by FileWriteTool.Preview).
+func GetWriteDescription(input FileWriteInput, stats string) string {
 	action := "Write"
 	if input.Append {
 		action = "Append"
 	}
-	return fmt.Sprintf("%s %d bytes to %s", action, len(input.Content), input.Path)
+	return fmt.Sprintf("%s to %s (%s)", action, input.Path, stats)
 }
@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"uhh/internal/filebackup"
+)
+
+func TestFileWriteToolPreviewShowsDiffBeforeExecute(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "greeting.txt")
+	if err := os.WriteFile(path, []byte("hello\n"), 0644); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	tool := NewFileWriteTool()
+	input := Input{
+		Raw:        `{"path": "greeting.txt", "content": "hello world\n"}`,
+		WorkingDir: dir,
+	}
+
+	resolved, diff, stats, err := tool.Preview(input)
+	if err != nil {
+		t.Fatalf("Preview returned unexpected error: %v", err)
+	}
+	if resolved != path {
+		t.Fatalf("Preview resolved path = %q, want %q", resolved, path)
+	}
+	if diff == "" {
+		t.Fatalf("Preview returned an empty diff for a changed file")
+	}
+	if stats == "" {
+		t.Fatalf("Preview returned empty stats")
+	}
+
+	// Preview must not have touched the file.
+	data, _ := os.ReadFile(path)
+	if string(data) != "hello\n" {
+		t.Fatalf("Preview mutated the file: got %q", data)
+	}
+
+	out, err := tool.Execute(context.Background(), input)
+	if err != nil || !out.Success {
+		t.Fatalf("Execute failed: %v, %+v", err, out)
+	}
+
+	data, _ = os.ReadFile(path)
+	if string(data) != "hello world\n" {
+		t.Fatalf("Execute wrote %q, want %q", data, "hello world\n")
+	}
+}
+
+func TestFileWriteToolOverwriteIsAtomicAndBackedUp(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"v":1}`), 0644); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	tool := NewFileWriteTool()
+	out, err := tool.Execute(context.Background(), Input{
+		Raw:        `{"path": "config.json", "content": "{\"v\":2}"}`,
+		WorkingDir: dir,
+	})
+	if err != nil || !out.Success {
+		t.Fatalf("Execute failed: %v, %+v", err, out)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != `{"v":2}` {
+		t.Fatalf("overwrite produced %q", data)
+	}
+
+	// The previous contents were snapshotted before the overwrite.
+	rec, ok, err := filebackup.Latest(path)
+	if err != nil || !ok {
+		t.Fatalf("no backup recorded for %s: %v", path, err)
+	}
+	if backup, _ := os.ReadFile(rec.BackupPath); string(backup) != `{"v":1}` {
+		t.Fatalf("backup holds %q, want %q", backup, `{"v":1}`)
+	}
+
+	// No leftover temp file from the atomic-write dance.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	for _, e := range entries {
+		if e.Name() != "config.json" {
+			t.Fatalf("unexpected leftover file %q after atomic write", e.Name())
+		}
+	}
+}
@@ -0,0 +1,413 @@
+// Package mcp implements a client for the Model Context Protocol, letting
+// uhh register tools exposed by external MCP servers (filesystem, git,
+// github, sqlite, etc.) into the regular tools.Registry without writing a
+// Go tool per integration.
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"uhh/internal/config"
+	"uhh/internal/tools"
+)
+
+// rpcRequest is a JSON-RPC 2.0 request as used by the MCP spec.
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int64       `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// rpcNotification is a JSON-RPC 2.0 notification: no id, and no response is
+// expected (used for "notifications/initialized" after the handshake).
+type rpcNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// rpcResponse is a JSON-RPC 2.0 response.
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// mcpProtocolVersion is the protocolVersion this client declares during the
+// initialize handshake.
+const mcpProtocolVersion = "2024-11-05"
+
+// initializeParams mirrors the MCP "initialize" request body.
+type initializeParams struct {
+	ProtocolVersion string                 `json:"protocolVersion"`
+	Capabilities    map[string]interface{} `json:"capabilities"`
+	ClientInfo      clientInfo             `json:"clientInfo"`
+}
+
+type clientInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// toolsListResult mirrors the MCP tools/list response.
+type toolsListResult struct {
+	Tools []remoteToolSpec `json:"tools"`
+}
+
+type remoteToolSpec struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+}
+
+// toolCallResult mirrors the MCP tools/call response.
+type toolCallResult struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	IsError bool `json:"isError"`
+}
+
+// Client speaks JSON-RPC to a single MCP server, either over a subprocess's
+// stdio or over HTTP.
+type Client struct {
+	name    string
+	nextID  int64
+	trusted bool
+
+	// stdio transport
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	mu     sync.Mutex
+
+	// http transport
+	httpURL    string
+	httpClient *http.Client
+}
+
+// Connect establishes a connection to the server described by cfg, either
+// spawning a subprocess (Command set) or preparing an HTTP client (URL set).
+func Connect(ctx context.Context, cfg config.MCPServerConfig) (*Client, error) {
+	c := &Client{name: cfg.Name, trusted: cfg.Trusted}
+
+	if cfg.Command != "" {
+		cmd := exec.CommandContext(ctx, cfg.Command, cfg.Args...)
+		for k, v := range cfg.Env {
+			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+		}
+
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return nil, fmt.Errorf("mcp[%s]: failed to open stdin: %w", cfg.Name, err)
+		}
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, fmt.Errorf("mcp[%s]: failed to open stdout: %w", cfg.Name, err)
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("mcp[%s]: failed to start server: %w", cfg.Name, err)
+		}
+
+		c.cmd = cmd
+		c.stdin = stdin
+		c.stdout = bufio.NewReader(stdout)
+	} else if cfg.URL != "" {
+		c.httpURL = cfg.URL
+		c.httpClient = &http.Client{Timeout: 30 * time.Second}
+	} else {
+		return nil, fmt.Errorf("mcp[%s]: server config must set either command or url", cfg.Name)
+	}
+
+	if err := c.initialize(ctx); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// initialize performs the handshake the MCP spec requires before any other
+// request: an "initialize" request (declaring our protocol version and
+// capabilities), followed by an "notifications/initialized" notification
+// once the server has replied. Spec-compliant servers reject tools/list and
+// other requests sent before this completes.
+func (c *Client) initialize(ctx context.Context) error {
+	params := initializeParams{
+		ProtocolVersion: mcpProtocolVersion,
+		Capabilities:    map[string]interface{}{},
+		ClientInfo:      clientInfo{Name: "uhh", Version: "0.1.0"},
+	}
+	if err := c.call(ctx, "initialize", params, nil); err != nil {
+		return fmt.Errorf("mcp[%s]: initialize failed: %w", c.name, err)
+	}
+	if err := c.notify(ctx, "notifications/initialized", struct{}{}); err != nil {
+		return fmt.Errorf("mcp[%s]: notifications/initialized failed: %w", c.name, err)
+	}
+	return nil
+}
+
+// Close shuts down the underlying transport.
+func (c *Client) Close() error {
+	if c.stdin != nil {
+		c.stdin.Close()
+	}
+	if c.cmd != nil && c.cmd.Process != nil {
+		return c.cmd.Process.Kill()
+	}
+	return nil
+}
+
+// call performs a single JSON-RPC request/response round trip.
+func (c *Client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	id := atomic.AddInt64(&c.nextID, 1)
+	req := rpcRequest{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  method,
+		Params:  params,
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	var raw json.RawMessage
+	if c.httpURL != "" {
+		raw, err = c.callHTTP(ctx, data)
+	} else {
+		raw, err = c.callStdio(data, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	var resp rpcResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return fmt.Errorf("mcp[%s]: invalid response: %w", c.name, err)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("mcp[%s]: %s (code %d)", c.name, resp.Error.Message, resp.Error.Code)
+	}
+	if result != nil {
+		return json.Unmarshal(resp.Result, result)
+	}
+	return nil
+}
+
+// notify sends a JSON-RPC notification: no id, and no response is read.
+func (c *Client) notify(ctx context.Context, method string, params interface{}) error {
+	data, err := json.Marshal(rpcNotification{JSONRPC: "2.0", Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+
+	if c.httpURL != "" {
+		_, err := c.callHTTP(ctx, data)
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err = c.stdin.Write(append(data, '\n'))
+	return err
+}
+
+// callStdio writes req and reads lines off stdout until it finds the
+// response whose id matches wantID, discarding anything else along the way:
+// a notification the server sends unprompted (no "id" field), a stray
+// non-JSON-RPC log line it wrote to stdout instead of stderr, or a response
+// to a different request. Calls are serialized by c.mu, so in practice the
+// matching response is the first JSON-RPC response line encountered, but we
+// still check the id rather than trusting that blindly.
+func (c *Client) callStdio(req []byte, wantID int64) (json.RawMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.stdin.Write(append(req, '\n')); err != nil {
+		return nil, fmt.Errorf("mcp[%s]: failed to write request: %w", c.name, err)
+	}
+
+	for {
+		line, err := c.stdout.ReadBytes('\n')
+		if err != nil {
+			return nil, fmt.Errorf("mcp[%s]: failed to read response: %w", c.name, err)
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var probe struct {
+			ID     *int64 `json:"id"`
+			Method string `json:"method"`
+		}
+		if err := json.Unmarshal(line, &probe); err != nil {
+			// Not valid JSON-RPC - e.g. a log line the server wrote to
+			// stdout instead of stderr. Not our response; keep reading.
+			continue
+		}
+		if probe.Method != "" || probe.ID == nil {
+			// A notification, or a request from the server to us - neither
+			// is a response.
+			continue
+		}
+		if *probe.ID != wantID {
+			// A reply to some other request; not ours.
+			continue
+		}
+		return json.RawMessage(line), nil
+	}
+}
+
+func (c *Client) callHTTP(ctx context.Context, req []byte) (json.RawMessage, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.httpURL, bytes.NewReader(req))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("mcp[%s]: request failed: %w", c.name, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(body), nil
+}
+
+// ListTools fetches the tools exposed by the server.
+func (c *Client) ListTools(ctx context.Context) ([]remoteToolSpec, error) {
+	var result toolsListResult
+	if err := c.call(ctx, "tools/list", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Tools, nil
+}
+
+// CallTool invokes a remote tool by name with raw JSON arguments.
+func (c *Client) CallTool(ctx context.Context, name string, rawArgs json.RawMessage) (string, error) {
+	params := map[string]interface{}{
+		"name":      name,
+		"arguments": json.RawMessage(rawArgs),
+	}
+
+	var result toolCallResult
+	if err := c.call(ctx, "tools/call", params, &result); err != nil {
+		return "", err
+	}
+
+	var sb bytes.Buffer
+	for _, part := range result.Content {
+		sb.WriteString(part.Text)
+	}
+
+	if result.IsError {
+		return "", fmt.Errorf("mcp[%s]: tool %s reported an error: %s", c.name, name, sb.String())
+	}
+
+	return sb.String(), nil
+}
+
+// remoteTool adapts a single MCP server tool to the tools.Tool interface.
+type remoteTool struct {
+	client  *Client
+	spec    remoteToolSpec
+	name    string
+	trusted bool
+}
+
+func (t *remoteTool) Name() string {
+	return t.name
+}
+
+func (t *remoteTool) Description() string {
+	return t.spec.Description
+}
+
+func (t *remoteTool) Parameters() map[string]interface{} {
+	if t.spec.InputSchema != nil {
+		return t.spec.InputSchema
+	}
+	return map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+}
+
+func (t *remoteTool) Execute(ctx context.Context, input tools.Input) (tools.Output, error) {
+	raw := input.Raw
+	if raw == "" {
+		raw = "{}"
+	}
+
+	result, err := t.client.CallTool(ctx, t.spec.Name, json.RawMessage(raw))
+	if err != nil {
+		return tools.NewErrorOutput(err), nil
+	}
+	return tools.NewOutput(result), nil
+}
+
+func (t *remoteTool) RequiresConfirmation() bool {
+	return !t.trusted
+}
+
+func (t *remoteTool) SafetyLevel() tools.SafetyLevel {
+	if t.trusted {
+		return tools.SafetyLevelModerate
+	}
+	return tools.SafetyLevelDangerous
+}
+
+// RegisterAll connects to each configured MCP server, lists its tools, and
+// registers them into registry under a "<server>_<tool>" name. Servers that
+// fail to connect are skipped with an error returned for logging; the rest
+// still get registered.
+func RegisterAll(ctx context.Context, registry *tools.Registry, servers []config.MCPServerConfig) []error {
+	var errs []error
+
+	for _, serverCfg := range servers {
+		client, err := Connect(ctx, serverCfg)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		specs, err := client.ListTools(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("mcp[%s]: failed to list tools: %w", serverCfg.Name, err))
+			client.Close()
+			continue
+		}
+
+		for _, spec := range specs {
+			registry.Register(&remoteTool{
+				client:  client,
+				spec:    spec,
+				name:    fmt.Sprintf("%s_%s", serverCfg.Name, spec.Name),
+				trusted: serverCfg.Trusted,
+			})
+		}
+	}
+
+	return errs
+}
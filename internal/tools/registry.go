@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"sync"
 
+	"uhh/internal/config"
+
 	"github.com/tmc/langchaingo/llms"
 )
 
@@ -96,7 +98,23 @@ func (r *Registry) FilterByNames(names []string) []Tool {
 	return tools
 }
 
-// DefaultRegistry creates a registry with all default tools
+// Subset returns a new Registry containing only the named tools, for
+// restricting which tools an agent.Profile exposes to the LLM. A nil or
+// empty names returns r unchanged (no restriction).
+func (r *Registry) Subset(names []string) *Registry {
+	if len(names) == 0 {
+		return r
+	}
+
+	sub := NewRegistry()
+	for _, tool := range r.FilterByNames(names) {
+		sub.Register(tool)
+	}
+	return sub
+}
+
+// DefaultRegistry creates a registry with all default tools, with bash and
+// file_write executing directly on the host (no sandbox).
 func DefaultRegistry() *Registry {
 	r := NewRegistry()
 	r.Register(NewBashTool())
@@ -104,3 +122,13 @@ func DefaultRegistry() *Registry {
 	r.Register(NewFileWriteTool())
 	return r
 }
+
+// DefaultRegistryWithConfig creates a registry with all default tools, with
+// bash and file_write isolated per shellCfg.Sandbox (see NewSandbox).
+func DefaultRegistryWithConfig(shellCfg config.ShellSettings) *Registry {
+	r := NewRegistry()
+	r.Register(NewBashToolWithConfig(shellCfg))
+	r.Register(NewFileReadTool())
+	r.Register(NewFileWriteToolWithConfig(shellCfg))
+	return r
+}
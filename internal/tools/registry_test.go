@@ -0,0 +1,34 @@
+package tools
+
+import "testing"
+
+func TestRegistrySubsetRestrictsToNamedTools(t *testing.T) {
+	r := NewRegistry()
+	r.Register(NewBashTool())
+	r.Register(NewFileReadTool())
+	r.Register(NewFileWriteTool())
+
+	sub := r.Subset([]string{"file_read"})
+
+	if !sub.Has("file_read") {
+		t.Fatalf("expected subset to contain file_read")
+	}
+	if sub.Has("bash") || sub.Has("file_write") {
+		t.Fatalf("expected subset to exclude tools outside the allow-list, got names %v", sub.Names())
+	}
+	if len(sub.Names()) != 1 {
+		t.Fatalf("expected exactly 1 tool in subset, got %d", len(sub.Names()))
+	}
+}
+
+func TestRegistrySubsetWithNoNamesReturnsUnrestricted(t *testing.T) {
+	r := NewRegistry()
+	r.Register(NewBashTool())
+	r.Register(NewFileReadTool())
+
+	sub := r.Subset(nil)
+
+	if len(sub.Names()) != len(r.Names()) {
+		t.Fatalf("expected an empty allow-list to leave the registry unrestricted, got %v", sub.Names())
+	}
+}
@@ -0,0 +1,191 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"uhh/internal/config"
+)
+
+// Sandbox isolates command execution (and, when FileWriteTool routes
+// through one, file writes) from the host filesystem and network. Which
+// implementation is active is controlled by config.ShellSettings.Sandbox.
+type Sandbox interface {
+	// Run executes cmd in workdir with the given extra environment
+	// variables and returns its captured stdout/stderr.
+	Run(ctx context.Context, cmd, workdir string, env []string) (stdout, stderr []byte, err error)
+
+	// Host reports whether this is the (unsandboxed) HostSandbox, so
+	// FileWriteTool knows whether it needs to confine paths to workdir
+	// itself rather than relying on the sandbox's own isolation.
+	Host() bool
+}
+
+const defaultSandboxImage = "alpine:3.19"
+
+// NewSandbox constructs the Sandbox selected by cfg.Sandbox. An unrecognized
+// or empty value falls back to HostSandbox, i.e. the pre-sandbox behavior.
+func NewSandbox(cfg config.ShellSettings) Sandbox {
+	network := cfg.SandboxNetwork
+	if network == "" {
+		network = "none"
+	}
+
+	switch cfg.Sandbox {
+	case "docker":
+		return &ContainerSandbox{binary: "docker", image: sandboxImage(cfg), readOnly: cfg.SandboxReadOnly, network: network}
+	case "podman":
+		return &ContainerSandbox{binary: "podman", image: sandboxImage(cfg), readOnly: cfg.SandboxReadOnly, network: network}
+	case "firejail":
+		return &FirejailSandbox{readOnly: cfg.SandboxReadOnly}
+	default:
+		return &HostSandbox{}
+	}
+}
+
+func sandboxImage(cfg config.ShellSettings) string {
+	if cfg.SandboxImage != "" {
+		return cfg.SandboxImage
+	}
+	return defaultSandboxImage
+}
+
+// HostSandbox runs commands directly on the host, which was BashTool's only
+// behavior before sandboxing was introduced.
+type HostSandbox struct{}
+
+// Run implements Sandbox.
+func (s *HostSandbox) Run(ctx context.Context, cmd, workdir string, env []string) ([]byte, []byte, error) {
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.CommandContext(ctx, "cmd", "/C", cmd)
+	} else {
+		c = exec.CommandContext(ctx, "sh", "-c", cmd)
+	}
+	if workdir != "" {
+		c.Dir = workdir
+	}
+	c.Env = append(c.Env, env...)
+
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	err := c.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+// Host implements Sandbox.
+func (s *HostSandbox) Host() bool { return true }
+
+// ContainerSandbox runs commands inside an ephemeral container launched via
+// the docker or podman CLI (both accept the same flags we use here), with
+// the working directory bind-mounted in, capabilities dropped, and
+// conservative resource limits so a runaway command can't take down the
+// host.
+type ContainerSandbox struct {
+	binary   string // "docker" or "podman"
+	image    string
+	readOnly bool
+	network  string
+}
+
+// Run implements Sandbox.
+func (s *ContainerSandbox) Run(ctx context.Context, cmd, workdir string, env []string) ([]byte, []byte, error) {
+	if workdir == "" {
+		return nil, nil, fmt.Errorf("%s sandbox requires a working directory to mount", s.binary)
+	}
+
+	mount := fmt.Sprintf("%s:/workspace", workdir)
+	if s.readOnly {
+		mount += ":ro"
+	}
+
+	args := []string{
+		"run", "--rm",
+		"-v", mount,
+		"-w", "/workspace",
+		"--network", s.network,
+		"--cap-drop", "ALL",
+		"--memory", "512m",
+		"--cpus", "1",
+		"--pids-limit", "256",
+	}
+	for _, e := range env {
+		args = append(args, "-e", e)
+	}
+	args = append(args, s.image, "sh", "-c", cmd)
+
+	c := exec.CommandContext(ctx, s.binary, args...)
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	err := c.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+// Host implements Sandbox.
+func (s *ContainerSandbox) Host() bool { return false }
+
+// FirejailSandbox runs commands under Linux's firejail, confined to a
+// private view of workdir. Firejail is Linux-only; Run returns an error on
+// other platforms.
+type FirejailSandbox struct {
+	readOnly bool
+}
+
+// Run implements Sandbox.
+func (s *FirejailSandbox) Run(ctx context.Context, cmd, workdir string, env []string) ([]byte, []byte, error) {
+	if runtime.GOOS != "linux" {
+		return nil, nil, fmt.Errorf("firejail sandbox is only supported on linux")
+	}
+	if workdir == "" {
+		return nil, nil, fmt.Errorf("firejail sandbox requires a working directory")
+	}
+
+	args := []string{"--quiet", "--net=none", "--private=" + workdir}
+	if s.readOnly {
+		args = append(args, "--read-only="+workdir)
+	}
+	args = append(args, "--", "sh", "-c", cmd)
+
+	c := exec.CommandContext(ctx, "firejail", args...)
+	c.Dir = workdir
+	c.Env = append(c.Env, env...)
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	err := c.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+// Host implements Sandbox.
+func (s *FirejailSandbox) Host() bool { return false }
+
+// sandboxConfine resolves rel against workdir and ensures the result does
+// not escape workdir, returning an error otherwise. FileWriteTool uses this
+// when a non-host sandbox is active, since a container/firejail mount makes
+// workdir the only path that's actually writable back to the host - an
+// "escape" attempt like "../../etc/passwd" must be rejected before it ever
+// reaches the sandbox.
+func sandboxConfine(workdir, rel string) (string, error) {
+	if workdir == "" {
+		return "", fmt.Errorf("sandbox requires a working directory")
+	}
+
+	abs := rel
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(workdir, abs)
+	}
+	abs = filepath.Clean(abs)
+
+	base := filepath.Clean(workdir)
+	if abs != base && !strings.HasPrefix(abs, base+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q escapes sandbox working directory %q", rel, workdir)
+	}
+	return abs, nil
+}
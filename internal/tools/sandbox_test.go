@@ -0,0 +1,150 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSandboxConfineRejectsEscape(t *testing.T) {
+	workdir := t.TempDir()
+
+	cases := []struct {
+		name    string
+		rel     string
+		wantErr bool
+	}{
+		{"inside workdir", "output.txt", false},
+		{"nested inside workdir", "sub/output.txt", false},
+		{"parent traversal", "../output.txt", true},
+		{"deep parent traversal", "../../etc/passwd", true},
+		{"absolute host path", "/etc/passwd", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			resolved, err := sandboxConfine(workdir, c.rel)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("sandboxConfine(%q, %q) = %q, want error", workdir, c.rel, resolved)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sandboxConfine(%q, %q) unexpected error: %v", workdir, c.rel, err)
+			}
+			base := filepath.Clean(workdir)
+			if resolved != base && !strings.HasPrefix(resolved, base+string(filepath.Separator)) {
+				t.Fatalf("sandboxConfine(%q, %q) = %q, want path under %q", workdir, c.rel, resolved, base)
+			}
+		})
+	}
+}
+
+// stubSandbox reports Host() == false without actually shelling out to a
+// container runtime, so FileWriteTool's confinement path can be exercised
+// without docker/podman/firejail installed.
+type stubSandbox struct{}
+
+func (stubSandbox) Run(ctx context.Context, cmd, workdir string, env []string) ([]byte, []byte, error) {
+	return nil, nil, nil
+}
+
+func (stubSandbox) Host() bool { return false }
+
+func TestFileWriteToolRejectsHostPathEscapeInSandboxMode(t *testing.T) {
+	workdir := t.TempDir()
+	tool := &FileWriteTool{Sandbox: stubSandbox{}}
+
+	out, err := tool.Execute(context.Background(), Input{
+		Raw:        `{"path": "../../etc/passwd", "content": "pwned"}`,
+		WorkingDir: workdir,
+	})
+	if err != nil {
+		t.Fatalf("Execute returned unexpected error: %v", err)
+	}
+	if out.Success {
+		t.Fatalf("Execute succeeded for an escaping path, want failure: %+v", out)
+	}
+
+	if _, statErr := os.Stat(filepath.Join(workdir, "..", "..", "etc", "passwd")); !os.IsNotExist(statErr) {
+		t.Fatalf("escape attempt appears to have written outside workdir")
+	}
+}
+
+// recordingSandbox reports Host() == false, like stubSandbox, but actually
+// runs the script FileWriteTool hands it (via the host shell) so a test can
+// confirm the write really happened through Sandbox.Run rather than being
+// written to disk directly by the host process.
+type recordingSandbox struct {
+	ran bool
+}
+
+func (s *recordingSandbox) Run(ctx context.Context, cmd, workdir string, env []string) ([]byte, []byte, error) {
+	s.ran = true
+	return (&HostSandbox{}).Run(ctx, cmd, workdir, env)
+}
+
+func (s *recordingSandbox) Host() bool { return false }
+
+// readOnlySandbox simulates a sandbox backed by a read-only mount: Run
+// always fails, as `docker run -v ...:ro` or firejail's --read-only would,
+// without ever touching the host filesystem itself.
+type readOnlySandbox struct{}
+
+func (readOnlySandbox) Run(ctx context.Context, cmd, workdir string, env []string) ([]byte, []byte, error) {
+	return nil, []byte("read-only file system"), fmt.Errorf("mount is read-only")
+}
+
+func (readOnlySandbox) Host() bool { return false }
+
+func TestFileWriteToolAllowsConfinedPathInSandboxMode(t *testing.T) {
+	workdir := t.TempDir()
+	sandbox := &recordingSandbox{}
+	tool := &FileWriteTool{Sandbox: sandbox}
+
+	out, err := tool.Execute(context.Background(), Input{
+		Raw:        `{"path": "notes.txt", "content": "hello"}`,
+		WorkingDir: workdir,
+	})
+	if err != nil {
+		t.Fatalf("Execute returned unexpected error: %v", err)
+	}
+	if !out.Success {
+		t.Fatalf("Execute failed for a confined path: %+v", out)
+	}
+	if !sandbox.ran {
+		t.Fatalf("expected the write to go through Sandbox.Run, but it was never called")
+	}
+
+	data, statErr := os.ReadFile(filepath.Join(workdir, "notes.txt"))
+	if statErr != nil {
+		t.Fatalf("expected file written inside workdir via the sandbox: %v", statErr)
+	}
+	if string(data) != "hello" {
+		t.Fatalf("file content = %q, want %q", data, "hello")
+	}
+}
+
+func TestFileWriteToolReadOnlySandboxDoesNotWriteHost(t *testing.T) {
+	workdir := t.TempDir()
+	tool := &FileWriteTool{Sandbox: readOnlySandbox{}}
+
+	out, err := tool.Execute(context.Background(), Input{
+		Raw:        `{"path": "notes.txt", "content": "hello"}`,
+		WorkingDir: workdir,
+	})
+	if err != nil {
+		t.Fatalf("Execute returned unexpected error: %v", err)
+	}
+	if out.Success {
+		t.Fatalf("Execute succeeded against a read-only sandbox, want failure: %+v", out)
+	}
+
+	if _, statErr := os.Stat(filepath.Join(workdir, "notes.txt")); !os.IsNotExist(statErr) {
+		t.Fatalf("read-only sandbox rejection still resulted in a host write")
+	}
+}
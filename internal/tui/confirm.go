@@ -2,8 +2,10 @@ package tui
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
 )
 
 // ConfirmToolExecution asks the user to confirm a tool execution
@@ -39,6 +41,57 @@ func ConfirmToolExecution(toolName, description, command string) (bool, error) {
 	return confirmed, nil
 }
 
+// ConfirmFileWrite asks the user to confirm a file_write tool call, showing
+// a colorized unified diff of the proposed change (additions green,
+// removals red, hunk headers cyan) above the confirm prompt.
+func ConfirmFileWrite(path, diff, stats string) (bool, error) {
+	var confirmed bool
+
+	body := fmt.Sprintf("File: %s\n%s\n\n%s", path, stats, colorizeDiff(diff))
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewNote().
+				Title("File Write Request").
+				Description(BoxStyle.Render(body)),
+			huh.NewConfirm().
+				Title("Write this file?").
+				Affirmative("Yes, write").
+				Negative("No, skip").
+				Value(&confirmed),
+		),
+	).WithTheme(GetTheme())
+
+	if err := form.Run(); err != nil {
+		return false, err
+	}
+
+	return confirmed, nil
+}
+
+// colorizeDiff renders a unified diff with lipgloss: additions green,
+// removals red, "@@" hunk headers cyan, everything else unstyled.
+func colorizeDiff(diff string) string {
+	if diff == "" {
+		return "(no changes)"
+	}
+
+	lines := strings.Split(diff, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---"):
+			// File headers, left unstyled.
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = lipgloss.NewStyle().Foreground(Secondary).Render(line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = lipgloss.NewStyle().Foreground(Success).Render(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = lipgloss.NewStyle().Foreground(Error).Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 // ConfirmDangerousOperation asks for confirmation before a dangerous operation
 func ConfirmDangerousOperation(operation, warning string) (bool, error) {
 	var confirmed bool
@@ -0,0 +1,188 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"uhh/internal/provider"
+
+	"github.com/manifoldco/promptui"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// Action is the user's choice after reviewing a candidate command.
+type Action string
+
+const (
+	ActionRun        Action = "Run"
+	ActionCopy       Action = "Copy"
+	ActionRegenerate Action = "Regenerate"
+	ActionCancel     Action = "Cancel"
+)
+
+// ConfirmDangerousInteractive asks for confirmation before a dangerous tool
+// execution using promptui, so dangerous agent tool calls share the same UI
+// surface as the interactive candidate-selection flow instead of the
+// huh-based form ConfirmToolExecution uses for everything else.
+func ConfirmDangerousInteractive(toolName, description, command string) (bool, error) {
+	label := fmt.Sprintf("[DANGEROUS] %s", toolName)
+	if command != "" {
+		label = fmt.Sprintf("[DANGEROUS] %s: %s", toolName, command)
+	} else if description != "" {
+		label = fmt.Sprintf("[DANGEROUS] %s: %s", toolName, description)
+	}
+
+	prompt := promptui.Prompt{
+		Label:     label,
+		IsConfirm: true,
+	}
+
+	if _, err := prompt.Run(); err != nil {
+		if err == promptui.ErrAbort {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// InteractiveEnabled reports whether the candidate-selection + confirmation
+// flow should be used in place of printing the first completion straight to
+// the clipboard, via --interactive or UHH_INTERACTIVE=1.
+func InteractiveEnabled(flag bool) bool {
+	if flag {
+		return true
+	}
+	v := strings.ToLower(os.Getenv("UHH_INTERACTIVE"))
+	return v == "1" || v == "true"
+}
+
+// GenerateCandidates asks the provider for n candidate completions of
+// prompt. Candidates are requested one call at a time rather than via
+// llms.WithN, since not every provider honors that option.
+func GenerateCandidates(ctx context.Context, p provider.Provider, prompt string, temperature float64, n int) ([]string, error) {
+	if n < 1 {
+		n = 1
+	}
+
+	candidates := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		completion, err := p.Call(ctx, prompt, llms.WithTemperature(temperature))
+		if err != nil {
+			return nil, fmt.Errorf("candidate %d/%d: %w", i+1, n, err)
+		}
+		candidates = append(candidates, strings.TrimSpace(completion))
+	}
+	return candidates, nil
+}
+
+// SelectCandidateAction shows candidates in a promptui.Select with a
+// syntax-highlighted preview, then offers Run/Copy/Edit/Regenerate/Cancel
+// before anything touches the clipboard or shell. Edit and Regenerate are
+// handled internally (the user stays in the loop); Run/Copy/Cancel are
+// returned to the caller, which owns the actual side effect.
+func SelectCandidateAction(candidates []string, regenerate func() ([]string, error)) (Action, string, error) {
+	for {
+		chosen, err := selectCandidate(candidates)
+		if err != nil {
+			return "", "", err
+		}
+
+		action, err := selectAction()
+		if err != nil {
+			return "", "", err
+		}
+
+		switch action {
+		case "Edit":
+			edited, err := editInEditor(chosen)
+			if err != nil {
+				return "", "", err
+			}
+			candidates = []string{edited}
+			continue
+		case string(ActionRegenerate):
+			fresh, err := regenerate()
+			if err != nil {
+				return "", "", err
+			}
+			candidates = fresh
+			continue
+		default:
+			return Action(action), chosen, nil
+		}
+	}
+}
+
+func selectCandidate(candidates []string) (string, error) {
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+
+	prompt := promptui.Select{
+		Label: "Choose a candidate command",
+		Items: candidates,
+		Size:  len(candidates),
+		Templates: &promptui.SelectTemplates{
+			Label:    "{{ . }}",
+			Active:   "▸ {{ . | cyan }}",
+			Inactive: "  {{ . | faint }}",
+			Selected: "{{ \"Command:\" | faint }} {{ . | cyan }}",
+		},
+	}
+
+	idx, _, err := prompt.Run()
+	if err != nil {
+		return "", err
+	}
+	return candidates[idx], nil
+}
+
+func selectAction() (string, error) {
+	prompt := promptui.Select{
+		Label: "What do you want to do?",
+		Items: []string{string(ActionRun), string(ActionCopy), "Edit", string(ActionRegenerate), string(ActionCancel)},
+	}
+
+	_, action, err := prompt.Run()
+	return action, err
+}
+
+// editInEditor opens command in $EDITOR (falling back to vi) and returns the
+// edited contents once the editor exits.
+func editInEditor(command string) (string, error) {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+
+	tmp, err := os.CreateTemp("", "uhh-edit-*.sh")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(command); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	tmp.Close()
+
+	cmd := exec.Command(editor, tmp.Name())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to launch $EDITOR (%s): %w", editor, err)
+	}
+
+	edited, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(edited)), nil
+}
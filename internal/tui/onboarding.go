@@ -25,6 +25,23 @@ var availableProviders = []ProviderOption{
 	{Name: config.ProviderDeepseek, DisplayName: "DeepSeek", EnvVar: "DEEPSEEK_API_KEY"},
 	{Name: config.ProviderKimi, DisplayName: "Kimi (Moonshot)", EnvVar: "MOONSHOT_API_KEY"},
 	{Name: config.ProviderGLM, DisplayName: "GLM (Zhipu AI)", EnvVar: "GLM_API_KEY"},
+	{Name: config.ProviderLocal, DisplayName: "Local / Self-hosted (Ollama, LM Studio, vLLM...)", EnvVar: ""},
+	{Name: config.ProviderAnthropic, DisplayName: "Anthropic (Claude)", EnvVar: "ANTHROPIC_API_KEY"},
+	{Name: config.ProviderOllama, DisplayName: "Ollama", EnvVar: ""},
+}
+
+// localPreset is one of the quick-pick base URLs offered for the local
+// provider; "custom" leaves BaseURL empty so the user is prompted for it.
+type localPreset struct {
+	Name    string
+	BaseURL string
+}
+
+var localPresets = []localPreset{
+	{Name: "Ollama (http://localhost:11434/v1)", BaseURL: "http://localhost:11434/v1"},
+	{Name: "LM Studio (http://localhost:1234/v1)", BaseURL: "http://localhost:1234/v1"},
+	{Name: "vLLM (http://localhost:8000/v1)", BaseURL: "http://localhost:8000/v1"},
+	{Name: "Custom URL", BaseURL: ""},
 }
 
 // OnboardingResult contains the result of the onboarding wizard
@@ -32,6 +49,7 @@ type OnboardingResult struct {
 	SelectedProviders []string
 	APIKeys           map[string]string
 	Models            map[string]string
+	BaseURLs          map[string]string
 	DefaultProvider   string
 	AutoApprove       bool
 }
@@ -39,8 +57,9 @@ type OnboardingResult struct {
 // RunOnboarding runs the onboarding wizard and returns the configuration
 func RunOnboarding() (*OnboardingResult, error) {
 	result := &OnboardingResult{
-		APIKeys: make(map[string]string),
-		Models:  make(map[string]string),
+		APIKeys:  make(map[string]string),
+		Models:   make(map[string]string),
+		BaseURLs: make(map[string]string),
 	}
 
 	// Welcome screen
@@ -84,6 +103,39 @@ func RunOnboarding() (*OnboardingResult, error) {
 			}
 		}
 
+		if providerName == config.ProviderLocal {
+			baseURL, apiKey, err := configureLocalProvider()
+			if err != nil {
+				return nil, err
+			}
+
+			result.BaseURLs[providerName] = baseURL
+			if apiKey != "" {
+				result.APIKeys[providerName] = apiKey
+			}
+
+			selectedModel, err := selectModelForBaseURL(baseURL, apiKey)
+			if err == nil && selectedModel != "" {
+				result.Models[providerName] = selectedModel
+			}
+			continue
+		}
+
+		if providerName == config.ProviderOllama {
+			baseURL, err := configureOllamaProvider()
+			if err != nil {
+				return nil, err
+			}
+
+			result.BaseURLs[providerName] = baseURL
+
+			selectedModel, err := selectModelForBaseURL(baseURL, "")
+			if err == nil && selectedModel != "" {
+				result.Models[providerName] = selectedModel
+			}
+			continue
+		}
+
 		var apiKey string
 		keyForm := huh.NewForm(
 			huh.NewGroup(
@@ -175,6 +227,9 @@ func ApplyOnboardingResult(cfg *config.Config, result *OnboardingResult) {
 			if model, hasModel := result.Models[name]; hasModel && model != "" {
 				settings.Model = model
 			}
+			if baseURL, hasBaseURL := result.BaseURLs[name]; hasBaseURL && baseURL != "" {
+				settings.BaseURL = baseURL
+			}
 			cfg.Providers[name] = settings
 		}
 	}
@@ -196,6 +251,147 @@ func ApplyOnboardingResult(cfg *config.Config, result *OnboardingResult) {
 	}
 }
 
+// configureLocalProvider prompts for a base URL (via preset or custom entry)
+// and an optional API key for a self-hosted OpenAI-compatible backend, then
+// probes the URL so a server that isn't running is caught here rather than
+// failing confusingly on the first real request.
+func configureLocalProvider() (baseURL, apiKey string, err error) {
+	presetOptions := make([]huh.Option[string], len(localPresets))
+	for i, p := range localPresets {
+		presetOptions[i] = huh.NewOption(p.Name, p.BaseURL)
+	}
+
+	var selectedBaseURL string
+	presetForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Which local/self-hosted server are you using?").
+				Options(presetOptions...).
+				Value(&selectedBaseURL),
+		).Title("Step 2: Local Provider"),
+	).WithTheme(GetTheme())
+
+	if err := presetForm.Run(); err != nil {
+		return "", "", err
+	}
+
+	if selectedBaseURL == "" {
+		urlForm := huh.NewForm(
+			huh.NewGroup(
+				huh.NewInput().
+					Title("Enter the base URL").
+					Description("e.g. http://localhost:8000/v1").
+					Placeholder("http://localhost:8000/v1").
+					Value(&selectedBaseURL),
+			),
+		).WithTheme(GetTheme())
+
+		if err := urlForm.Run(); err != nil {
+			return "", "", err
+		}
+	}
+
+	keyForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("API key (optional)").
+				Description("Most local servers don't require one - leave blank if so.").
+				EchoMode(huh.EchoModePassword).
+				Value(&apiKey),
+		),
+	).WithTheme(GetTheme())
+
+	if err := keyForm.Run(); err != nil {
+		return "", "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := provider.ProbeBaseURL(ctx, selectedBaseURL); err != nil {
+		fmt.Printf("%s Couldn't reach %s - make sure the server is running before you use uhh.\n", FormatDim("⚠"), selectedBaseURL)
+	}
+
+	return selectedBaseURL, apiKey, nil
+}
+
+// configureOllamaProvider prompts for Ollama's server URL, defaulting to
+// its standard localhost address, and probes it the same way
+// configureLocalProvider does. Ollama gets its own onboarding step (rather
+// than going through the generic local-provider preset list) because it's
+// a first-class provider with a fixed, well-known default.
+func configureOllamaProvider() (baseURL string, err error) {
+	baseURL = config.DefaultBaseURLs[config.ProviderOllama]
+
+	urlForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Ollama server URL").
+				Description("Leave as-is unless Ollama is running elsewhere.").
+				Placeholder(baseURL).
+				Value(&baseURL),
+		).Title("Step 2: Ollama Configuration"),
+	).WithTheme(GetTheme())
+
+	if err := urlForm.Run(); err != nil {
+		return "", err
+	}
+
+	if baseURL == "" {
+		baseURL = config.DefaultBaseURLs[config.ProviderOllama]
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := provider.ProbeBaseURL(ctx, baseURL); err != nil {
+		fmt.Printf("%s Couldn't reach %s - make sure Ollama is running before you use uhh.\n", FormatDim("⚠"), baseURL)
+	}
+
+	return baseURL, nil
+}
+
+// selectModelForBaseURL lets the user pick a model from a self-hosted
+// backend at baseURL. Unlike selectModelForProvider it has no
+// provider-specific default model or fallback list - a self-hosted server
+// either reports its models or it doesn't.
+func selectModelForBaseURL(baseURL, apiKey string) (string, error) {
+	fmt.Printf("%s Fetching available models...\n", FormatDim("→"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	models, err := provider.ListModelsFromBaseURL(ctx, baseURL, apiKey)
+	if err != nil || len(models) == 0 {
+		fmt.Printf("%s Couldn't list models from %s; enter the model name manually later with 'uhh config models'.\n", FormatDim("→"), baseURL)
+		return "", fmt.Errorf("no models available")
+	}
+
+	options := make([]huh.Option[string], 0, len(models))
+	for _, m := range models {
+		label := m.ID
+		if m.Name != "" && m.Name != m.ID {
+			label = fmt.Sprintf("%s (%s)", m.Name, m.ID)
+		}
+		options = append(options, huh.NewOption(label, m.ID))
+	}
+
+	selectedModel := models[0].ID
+	modelForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Select model").
+				Description(fmt.Sprintf("Found %d available models", len(models))).
+				Options(options...).
+				Value(&selectedModel),
+		),
+	).WithTheme(GetTheme())
+
+	if err := modelForm.Run(); err != nil {
+		return "", err
+	}
+
+	return selectedModel, nil
+}
+
 // selectModelForProvider fetches available models and lets the user select one
 func selectModelForProvider(providerName, displayName, apiKey string) (string, error) {
 	fmt.Printf("%s Fetching available models...\n", FormatDim("→"))
@@ -286,6 +482,18 @@ func RunModelSelection(cfg *config.Config, providerName string) (string, error)
 	displayName := config.ProviderDisplayNames[providerName]
 	apiKey := settings.APIKey
 
+	if providerName == config.ProviderLocal || providerName == config.ProviderOllama {
+		baseURL := settings.BaseURL
+		if baseURL == "" {
+			if providerName == config.ProviderOllama {
+				baseURL = config.DefaultBaseURLs[config.ProviderOllama]
+			} else {
+				return "", fmt.Errorf("no base URL configured for %s", displayName)
+			}
+		}
+		return selectModelForBaseURL(baseURL, apiKey)
+	}
+
 	if apiKey == "" {
 		return "", fmt.Errorf("no API key configured for %s", displayName)
 	}
@@ -301,7 +509,7 @@ func ListAvailableModels(cfg *config.Config, providerName string) ([]provider.Mo
 	}
 
 	apiKey := settings.APIKey
-	if apiKey == "" {
+	if apiKey == "" && providerName != config.ProviderLocal && providerName != config.ProviderOllama {
 		return nil, fmt.Errorf("no API key configured for %s", providerName)
 	}
 
@@ -0,0 +1,149 @@
+package updater
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// osSynonyms maps runtime.GOOS to the tokens release assets commonly use
+// for it, since release pipelines don't agree on naming.
+var osSynonyms = map[string][]string{
+	"darwin":  {"darwin", "macos", "osx"},
+	"linux":   {"linux"},
+	"windows": {"windows", "win"},
+}
+
+// archSynonyms maps runtime.GOARCH to its common naming variants.
+var archSynonyms = map[string][]string{
+	"amd64": {"amd64", "x86_64", "x64"},
+	"arm64": {"arm64", "aarch64"},
+	"arm":   {"arm", "armv6l", "armv7l"},
+	"386":   {"386", "i386", "x86"},
+}
+
+// archiveExtensions are the archive formats matchAsset recognizes; an asset
+// ending in one of them scores higher than one that doesn't.
+var archiveExtensions = []string{".tar.gz", ".tgz", ".zip", ".tar.xz"}
+
+// AssetMatchError is returned when no release asset scores as a plausible
+// match for the running platform. It lists every asset name considered so
+// the caller can show the user why detection failed instead of just
+// reporting a bare "not found".
+type AssetMatchError struct {
+	GOOS, GOARCH string
+	Candidates   []string
+}
+
+func (e *AssetMatchError) Error() string {
+	return fmt.Sprintf("no release asset matched %s/%s (found: %s)", e.GOOS, e.GOARCH, strings.Join(e.Candidates, ", "))
+}
+
+// matchAsset scores each asset in assets against the current platform's OS
+// and architecture synonyms plus a recognized archive extension, and
+// returns the highest-scoring one. An asset needs at least an OS-token hit
+// and either an arch-token hit or a "universal" darwin build to be
+// considered; ties are broken by preferring the archive format native to
+// the current GOOS (.zip on Windows, .tar.gz elsewhere).
+func matchAsset(assets []Asset) (*Asset, error) {
+	osTokens := osSynonyms[runtime.GOOS]
+	archTokens := append([]string{}, archSynonyms[runtime.GOARCH]...)
+	if runtime.GOOS == "darwin" {
+		archTokens = append(archTokens, "universal")
+	}
+
+	names := make([]string, len(assets))
+	var best *Asset
+	bestScore := 0
+	bestIsNative := false
+
+	for i := range assets {
+		names[i] = assets[i].Name
+		name := strings.ToLower(assets[i].Name)
+
+		ext := matchExtension(name)
+		hasOS := containsAny(name, osTokens)
+		hasArch := containsAny(name, archTokens)
+
+		if !hasOS || !hasArch {
+			continue
+		}
+
+		score := 2
+		if ext != "" {
+			score++
+		}
+		isNative := ext == nativeExtension()
+
+		if best == nil || score > bestScore || (score == bestScore && isNative && !bestIsNative) {
+			best = &assets[i]
+			bestScore = score
+			bestIsNative = isNative
+		}
+	}
+
+	if best == nil {
+		return nil, &AssetMatchError{GOOS: runtime.GOOS, GOARCH: runtime.GOARCH, Candidates: names}
+	}
+	return best, nil
+}
+
+// containsAny reports whether haystack contains any of tokens as a whole
+// word, not merely as a substring - "arm" must not match inside "arm64" or
+// "aarch64", and "x86" must not match inside "x86_64", even though release
+// pipelines join fields with '-', '_', or '.' inconsistently enough that we
+// can't just split the name on a fixed set of delimiters and compare segments.
+func containsAny(haystack string, tokens []string) bool {
+	for _, t := range tokens {
+		if t != "" && containsToken(haystack, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsToken reports whether token appears in haystack bounded on both
+// sides by either a non-alphanumeric character ('-', '_', '.', ...) or the
+// start/end of the string. A match directly followed by "_64" is not a
+// boundary, so "x86" does not match inside "x86_64" while "x86_64" itself
+// still does.
+func containsToken(haystack, token string) bool {
+	for start := 0; ; {
+		idx := strings.Index(haystack[start:], token)
+		if idx < 0 {
+			return false
+		}
+		matchStart := start + idx
+		matchEnd := matchStart + len(token)
+
+		beforeIsBoundary := matchStart == 0 || !isWordByte(haystack[matchStart-1])
+		afterIsBoundary := (matchEnd == len(haystack) || !isWordByte(haystack[matchEnd])) &&
+			!strings.HasPrefix(haystack[matchEnd:], "_64")
+		if beforeIsBoundary && afterIsBoundary {
+			return true
+		}
+		start = matchStart + 1
+	}
+}
+
+func isWordByte(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+func matchExtension(name string) string {
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(name, ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
+// nativeExtension is the archive format getBinaryName's platform ships by
+// convention, used as matchAsset's tie-breaker.
+func nativeExtension() string {
+	if runtime.GOOS == "windows" {
+		return ".zip"
+	}
+	return ".tar.gz"
+}
@@ -0,0 +1,204 @@
+package updater
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"uhh/internal/config"
+)
+
+const defaultMaxBackups = 3
+
+// BackupInfo describes one previously installed binary kept by the updater
+// so `uhh update rollback` can restore it.
+type BackupInfo struct {
+	Version   string    `json:"version"`
+	Channel   string    `json:"channel"`
+	SHA256    string    `json:"sha256"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// Path is the backed-up binary's location; it isn't stored in the
+	// manifest since it's derived from Version via backupBinaryPath.
+	Path string `json:"-"`
+}
+
+// BackupsDir returns the directory holding backed-up binaries and their
+// manifests, creating it if necessary.
+func BackupsDir() (string, error) {
+	configDir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(configDir, "backups")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func backupBinaryPath(dir, version string) string {
+	name := fmt.Sprintf("uhh-%s", version)
+	if strings.HasSuffix(getBinaryName(), ".exe") {
+		name += ".exe"
+	}
+	return filepath.Join(dir, name)
+}
+
+func backupManifestPath(dir, version string) string {
+	return filepath.Join(dir, fmt.Sprintf("uhh-%s.json", version))
+}
+
+// createBackup copies execPath (the binary about to be replaced) into
+// BackupsDir, records a manifest alongside it, and prunes older backups
+// beyond maxBackups (0 uses defaultMaxBackups).
+func createBackup(execPath, version, channel string, maxBackups int) error {
+	dir, err := BackupsDir()
+	if err != nil {
+		return err
+	}
+
+	binPath := backupBinaryPath(dir, version)
+	if err := copyFile(execPath, binPath); err != nil {
+		return err
+	}
+	if err := os.Chmod(binPath, 0755); err != nil {
+		return err
+	}
+
+	sum, err := sha256File(binPath)
+	if err != nil {
+		return err
+	}
+
+	info := BackupInfo{
+		Version:   version,
+		Channel:   channel,
+		SHA256:    sum,
+		Timestamp: time.Now(),
+	}
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(backupManifestPath(dir, version), data, 0644); err != nil {
+		return err
+	}
+
+	return pruneBackups(maxBackups)
+}
+
+// pruneBackups removes the oldest backups beyond maxBackups (0 uses
+// defaultMaxBackups), deleting both the binary and its manifest.
+func pruneBackups(maxBackups int) error {
+	if maxBackups <= 0 {
+		maxBackups = defaultMaxBackups
+	}
+
+	backups, err := ListBackups()
+	if err != nil {
+		return err
+	}
+	if len(backups) <= maxBackups {
+		return nil
+	}
+
+	dir, err := BackupsDir()
+	if err != nil {
+		return err
+	}
+
+	for _, b := range backups[maxBackups:] {
+		os.Remove(backupBinaryPath(dir, b.Version))
+		os.Remove(backupManifestPath(dir, b.Version))
+	}
+	return nil
+}
+
+// ListBackups returns all known backups, newest first.
+func ListBackups() ([]BackupInfo, error) {
+	dir, err := BackupsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []BackupInfo
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		var info BackupInfo
+		if err := json.Unmarshal(data, &info); err != nil {
+			continue
+		}
+		info.Path = backupBinaryPath(dir, info.Version)
+		backups = append(backups, info)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Timestamp.After(backups[j].Timestamp)
+	})
+
+	return backups, nil
+}
+
+// RollbackTo restores a previously installed binary over the current
+// executable. An empty version rolls back to the most recent backup.
+// The backup's recorded SHA-256 is re-checked before installing it, so a
+// tampered or truncated backup file is rejected rather than installed.
+func RollbackTo(version string) error {
+	backups, err := ListBackups()
+	if err != nil {
+		return err
+	}
+	if len(backups) == 0 {
+		return fmt.Errorf("no backups available")
+	}
+
+	var target *BackupInfo
+	if version == "" {
+		target = &backups[0]
+	} else {
+		for i := range backups {
+			if backups[i].Version == version {
+				target = &backups[i]
+				break
+			}
+		}
+		if target == nil {
+			return fmt.Errorf("no backup found for version %s", version)
+		}
+	}
+
+	sum, err := sha256File(target.Path)
+	if err != nil {
+		return fmt.Errorf("backup binary missing or unreadable: %w", err)
+	}
+	if !strings.EqualFold(sum, target.SHA256) {
+		return fmt.Errorf("backup for %s failed integrity check (expected %s, got %s)", target.Version, target.SHA256, sum)
+	}
+
+	execPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to get executable path: %w", err)
+	}
+	execPath, err = filepath.EvalSymlinks(execPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve executable path: %w", err)
+	}
+
+	return replaceBinary(execPath, target.Path)
+}
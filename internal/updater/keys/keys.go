@@ -0,0 +1,16 @@
+// Package keys embeds the public key used to verify signed release
+// checksums (see updater.verifySignature). The matching private key is held
+// by the release pipeline and never checked into this repo; swap
+// release.pub for the real signing key's public half before cutting a
+// signed release.
+package keys
+
+import _ "embed"
+
+// ReleasePublicKey is a minisign/signify-format public key: a single line of
+// base64 (after the "untrusted comment:" line) decoding to a 2-byte
+// algorithm tag ("Ed" for Ed25519), an 8-byte key ID, and the 32-byte
+// public key itself.
+//
+//go:embed release.pub
+var ReleasePublicKey string
@@ -0,0 +1,114 @@
+package updater
+
+import (
+	"strconv"
+	"strings"
+)
+
+// semver is a minimal semantic version: a 3-component core plus an optional
+// dot-separated prerelease identifier list (e.g. "1.2.0-beta.3" parses to
+// core [1,2,0], prerelease ["beta","3"]). Build metadata ("+...") is ignored
+// since it carries no precedence per the semver spec.
+type semver struct {
+	core       [3]int
+	prerelease []string
+}
+
+// parseSemver parses a (possibly malformed) version string leniently -
+// missing or non-numeric core components just read as 0, matching the
+// forgiving style of the old compareVersions this replaces.
+func parseSemver(v string) semver {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+
+	core := v
+	var prerelease []string
+	if idx := strings.IndexAny(v, "-+"); idx >= 0 {
+		core = v[:idx]
+		if v[idx] == '-' {
+			rest := v[idx+1:]
+			if plus := strings.IndexByte(rest, '+'); plus >= 0 {
+				rest = rest[:plus]
+			}
+			if rest != "" {
+				prerelease = strings.Split(rest, ".")
+			}
+		}
+	}
+
+	var sv semver
+	parts := strings.Split(core, ".")
+	for i := 0; i < len(sv.core) && i < len(parts); i++ {
+		n, _ := strconv.Atoi(parts[i])
+		sv.core[i] = n
+	}
+	sv.prerelease = prerelease
+	return sv
+}
+
+// compareSemver orders two parsed versions per semver precedence rules:
+// core components compare numerically, and a version with a prerelease is
+// lower than the same core without one (1.2.0-beta.1 < 1.2.0).
+func compareSemver(a, b semver) int {
+	for i := 0; i < len(a.core); i++ {
+		if a.core[i] != b.core[i] {
+			if a.core[i] < b.core[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	if len(a.prerelease) == 0 && len(b.prerelease) == 0 {
+		return 0
+	}
+	if len(a.prerelease) == 0 {
+		return 1
+	}
+	if len(b.prerelease) == 0 {
+		return -1
+	}
+
+	for i := 0; i < len(a.prerelease) || i < len(b.prerelease); i++ {
+		if i >= len(a.prerelease) {
+			return -1
+		}
+		if i >= len(b.prerelease) {
+			return 1
+		}
+		if c := comparePrereleaseIdentifier(a.prerelease[i], b.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// comparePrereleaseIdentifier compares one dot-separated prerelease field.
+// Per semver, numeric identifiers compare numerically and always sort
+// before alphanumeric ones; otherwise identifiers compare as strings.
+func comparePrereleaseIdentifier(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+
+	if aErr == nil && bErr == nil {
+		if an < bn {
+			return -1
+		}
+		if an > bn {
+			return 1
+		}
+		return 0
+	}
+	if aErr == nil {
+		return -1
+	}
+	if bErr == nil {
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// compareVersions compares two semver strings.
+// Returns -1 if v1 < v2, 0 if equal, 1 if v1 > v2.
+func compareVersions(v1, v2 string) int {
+	return compareSemver(parseSemver(v1), parseSemver(v2))
+}
@@ -12,19 +12,33 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+
+	"github.com/ulikunitz/xz"
+)
+
+const (
+	repoOwner   = "nokusukun"
+	repoName    = "uhh"
+	apiURL      = "https://api.github.com/repos/" + repoOwner + "/" + repoName + "/releases/latest"
+	releasesURL = "https://api.github.com/repos/" + repoOwner + "/" + repoName + "/releases"
 )
 
+// Release channels, selected via cfg.Update.Channel or `uhh update --channel`.
+// Stable only considers non-prerelease tags; beta and nightly match tags
+// containing their name (e.g. "v1.3.0-beta.1", "v1.3.0-nightly.20260726").
 const (
-	repoOwner = "nokusukun"
-	repoName  = "uhh"
-	apiURL    = "https://api.github.com/repos/" + repoOwner + "/" + repoName + "/releases/latest"
+	ChannelStable  = "stable"
+	ChannelBeta    = "beta"
+	ChannelNightly = "nightly"
 )
 
 // Release represents a GitHub release
 type Release struct {
-	TagName string  `json:"tag_name"`
-	Assets  []Asset `json:"assets"`
-	HTMLURL string  `json:"html_url"`
+	TagName    string  `json:"tag_name"`
+	Assets     []Asset `json:"assets"`
+	HTMLURL    string  `json:"html_url"`
+	Prerelease bool    `json:"prerelease"`
+	Draft      bool    `json:"draft"`
 }
 
 // Asset represents a release asset
@@ -41,11 +55,41 @@ type UpdateInfo struct {
 	AssetName      string
 	ReleaseURL     string
 	HasUpdate      bool
+
+	// Channel is the release channel this update was resolved against
+	// (ChannelStable, ChannelBeta, or ChannelNightly). PerformUpdate uses it
+	// to re-resolve the release for verification, and createBackup records
+	// it in the backup manifest.
+	Channel string
+
+	// Verified is set by PerformUpdate once the release's checksums.txt
+	// signature has verified against keys.ReleasePublicKey. It stays false
+	// if the release doesn't publish a signature, or if RequireSignature
+	// was off and the signature check was skipped.
+	Verified bool
+}
+
+// UpdateOptions configures PerformUpdate's verification behavior.
+type UpdateOptions struct {
+	// RequireSignature fails the update unless the release's checksums.txt
+	// is signed and the signature verifies against keys.ReleasePublicKey.
+	// When false, a missing or unverifiable signature just leaves
+	// UpdateInfo.Verified false - the checksum check is mandatory either way.
+	RequireSignature bool
+
+	// VerifyOnly runs the full download/verify/extract pipeline but stops
+	// short of replacing the running binary, for `uhh update --verify-only`.
+	VerifyOnly bool
+
+	// MaxBackups caps how many prior binaries PerformUpdate keeps under
+	// BackupsDir(); 0 falls back to the default of 3. See pruneBackups.
+	MaxBackups int
 }
 
-// CheckForUpdate checks if a newer version is available
-func CheckForUpdate(currentVersion string) (*UpdateInfo, error) {
-	release, err := fetchLatestRelease()
+// CheckForUpdate checks if a newer version is available on the given
+// release channel (ChannelStable, ChannelBeta, or ChannelNightly).
+func CheckForUpdate(currentVersion, channel string) (*UpdateInfo, error) {
+	release, err := fetchLatestReleaseForChannel(channel)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check for updates: %w", err)
 	}
@@ -54,6 +98,7 @@ func CheckForUpdate(currentVersion string) (*UpdateInfo, error) {
 		CurrentVersion: currentVersion,
 		LatestVersion:  release.TagName,
 		ReleaseURL:     release.HTMLURL,
+		Channel:        channel,
 	}
 
 	// Compare versions (strip 'v' prefix if present)
@@ -68,24 +113,23 @@ func CheckForUpdate(currentVersion string) (*UpdateInfo, error) {
 	}
 
 	// Find the appropriate asset for this platform
-	assetName := getAssetName(release.TagName)
-	for _, asset := range release.Assets {
-		if asset.Name == assetName {
-			info.DownloadURL = asset.BrowserDownloadURL
-			info.AssetName = asset.Name
-			break
+	if info.HasUpdate {
+		asset, err := matchAsset(release.Assets)
+		if err != nil {
+			return nil, err
 		}
-	}
-
-	if info.HasUpdate && info.DownloadURL == "" {
-		return nil, fmt.Errorf("no release asset found for %s/%s", runtime.GOOS, runtime.GOARCH)
+		info.DownloadURL = asset.BrowserDownloadURL
+		info.AssetName = asset.Name
 	}
 
 	return info, nil
 }
 
-// PerformUpdate downloads and installs the update
-func PerformUpdate(info *UpdateInfo) error {
+// PerformUpdate downloads, verifies, and installs the update described by
+// info, per opts. Verification failures delete the temp directory (via the
+// deferred cleanup below) and return a descriptive error without touching
+// the running binary.
+func PerformUpdate(info *UpdateInfo, opts UpdateOptions) error {
 	if !info.HasUpdate {
 		return fmt.Errorf("no update available")
 	}
@@ -113,21 +157,54 @@ func PerformUpdate(info *UpdateInfo) error {
 		return fmt.Errorf("failed to download update: %w", err)
 	}
 
-	// Extract the binary
-	binaryPath := filepath.Join(tempDir, getBinaryName())
-	if strings.HasSuffix(info.AssetName, ".zip") {
+	release, err := fetchLatestReleaseForChannel(info.Channel)
+	if err != nil {
+		return fmt.Errorf("failed to verify update: %w", err)
+	}
+
+	verified, checksums, err := verifyRelease(release, info.AssetName, archivePath, opts.RequireSignature)
+	if err != nil {
+		return fmt.Errorf("update verification failed: %w", err)
+	}
+	info.Verified = verified
+
+	// Extract the archive, whatever format it came in as.
+	switch {
+	case strings.HasSuffix(info.AssetName, ".zip"):
 		if err := extractZip(archivePath, tempDir); err != nil {
 			return fmt.Errorf("failed to extract update: %w", err)
 		}
-	} else if strings.HasSuffix(info.AssetName, ".tar.gz") {
+	case strings.HasSuffix(info.AssetName, ".tar.gz"), strings.HasSuffix(info.AssetName, ".tgz"):
 		if err := extractTarGz(archivePath, tempDir); err != nil {
 			return fmt.Errorf("failed to extract update: %w", err)
 		}
+	case strings.HasSuffix(info.AssetName, ".tar.xz"):
+		if err := extractTarXz(archivePath, tempDir); err != nil {
+			return fmt.Errorf("failed to extract update: %w", err)
+		}
+	default:
+		return fmt.Errorf("unrecognized archive format for %s", info.AssetName)
+	}
+
+	// The binary isn't always at the archive's top level (some pipelines
+	// nest it under a version- or platform-named directory), so search the
+	// whole extracted tree for it.
+	binaryPath, err := findExtractedBinary(tempDir)
+	if err != nil {
+		return err
 	}
 
-	// Verify the binary exists
-	if _, err := os.Stat(binaryPath); os.IsNotExist(err) {
-		return fmt.Errorf("extracted binary not found at %s", binaryPath)
+	if err := verifyBinaryChecksumIfPresent(checksums, binaryPath, getBinaryName()); err != nil {
+		return fmt.Errorf("update verification failed: %w", err)
+	}
+
+	if opts.VerifyOnly {
+		return nil
+	}
+
+	// Keep a copy of the binary being replaced so RollbackTo can restore it.
+	if err := createBackup(execPath, info.CurrentVersion, info.Channel, opts.MaxBackups); err != nil {
+		return fmt.Errorf("failed to back up current binary: %w", err)
 	}
 
 	// Replace the current binary
@@ -157,16 +234,69 @@ func fetchLatestRelease() (*Release, error) {
 	return &release, nil
 }
 
-func getAssetName(version string) string {
-	goos := runtime.GOOS
-	goarch := runtime.GOARCH
+// fetchReleases lists the repository's releases, newest first, as returned
+// by the GitHub API.
+func fetchReleases() ([]Release, error) {
+	resp, err := http.Get(releasesURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	var releases []Release
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, err
+	}
+
+	return releases, nil
+}
+
+// fetchLatestReleaseForChannel returns the newest release matching channel.
+// ChannelStable falls back to fetchLatestRelease (GitHub's own "latest",
+// which already excludes prereleases and drafts); beta and nightly scan the
+// full release list for tag names matching the channel.
+func fetchLatestReleaseForChannel(channel string) (*Release, error) {
+	if channel == "" || channel == ChannelStable {
+		return fetchLatestRelease()
+	}
 
-	ext := ".tar.gz"
-	if goos == "windows" {
-		ext = ".zip"
+	releases, err := fetchReleases()
+	if err != nil {
+		return nil, err
 	}
 
-	return fmt.Sprintf("uhh-%s-%s-%s%s", version, goos, goarch, ext)
+	var best *Release
+	for i := range releases {
+		r := &releases[i]
+		if r.Draft || !channelMatches(*r, channel) {
+			continue
+		}
+		if best == nil || compareVersions(r.TagName, best.TagName) > 0 {
+			best = r
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("no releases found on the %s channel", channel)
+	}
+	return best, nil
+}
+
+// channelMatches reports whether release r belongs to channel, based on its
+// prerelease flag and tag name.
+func channelMatches(r Release, channel string) bool {
+	switch channel {
+	case ChannelStable:
+		return !r.Prerelease
+	case ChannelBeta, ChannelNightly:
+		return r.Prerelease && strings.Contains(r.TagName, channel)
+	default:
+		return strings.Contains(r.TagName, channel)
+	}
 }
 
 func getBinaryName() string {
@@ -244,6 +374,84 @@ func extractTarGz(archivePath, destDir string) error {
 	return nil
 }
 
+func extractTarXz(archivePath, destDir string) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	xzr, err := xz.NewReader(file)
+	if err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(xzr)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(destDir, header.Name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			outFile, err := os.OpenFile(target, os.O_CREATE|os.O_RDWR|os.O_TRUNC, os.FileMode(header.Mode))
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(outFile, tr); err != nil {
+				outFile.Close()
+				return err
+			}
+			outFile.Close()
+		}
+	}
+
+	return nil
+}
+
+// findExtractedBinary walks root looking for a regular file named
+// getBinaryName(), case-insensitively, since the binary isn't guaranteed to
+// sit at the archive's top level.
+func findExtractedBinary(root string) (string, error) {
+	want := strings.ToLower(getBinaryName())
+	var found string
+
+	err := filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if found != "" || fi.IsDir() {
+			return nil
+		}
+		if strings.ToLower(fi.Name()) == want {
+			found = path
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to search extracted archive: %w", err)
+	}
+	if found == "" {
+		return "", fmt.Errorf("extracted archive has no file named %s", getBinaryName())
+	}
+
+	return found, nil
+}
+
 func extractZip(archivePath, destDir string) error {
 	r, err := zip.OpenReader(archivePath)
 	if err != nil {
@@ -352,35 +560,3 @@ func copyFile(src, dst string) error {
 	_, err = io.Copy(destFile, sourceFile)
 	return err
 }
-
-// compareVersions compares two semver strings
-// Returns -1 if v1 < v2, 0 if equal, 1 if v1 > v2
-func compareVersions(v1, v2 string) int {
-	// Simple version comparison - split by dots and compare numerically
-	parts1 := strings.Split(v1, ".")
-	parts2 := strings.Split(v2, ".")
-
-	maxLen := len(parts1)
-	if len(parts2) > maxLen {
-		maxLen = len(parts2)
-	}
-
-	for i := 0; i < maxLen; i++ {
-		var n1, n2 int
-		if i < len(parts1) {
-			fmt.Sscanf(parts1[i], "%d", &n1)
-		}
-		if i < len(parts2) {
-			fmt.Sscanf(parts2[i], "%d", &n2)
-		}
-
-		if n1 < n2 {
-			return -1
-		}
-		if n1 > n2 {
-			return 1
-		}
-	}
-
-	return 0
-}
@@ -0,0 +1,230 @@
+package updater
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"uhh/internal/updater/keys"
+)
+
+const (
+	checksumsAssetName = "checksums.txt"
+	signatureAssetName = "checksums.txt.minisig"
+)
+
+// verifyRelease downloads the release's checksums.txt (and, if published,
+// its minisign signature), confirms assetPath's SHA-256 matches the entry
+// for assetName, and reports whether the checksums file's signature
+// verified against keys.ReleasePublicKey. requireSignature turns a missing
+// or invalid signature into a hard failure instead of just leaving
+// verified=false; the checksum check itself is always mandatory.
+// The downloaded checksums.txt bytes are returned so the caller can check
+// the extracted binary against it too, without a second download.
+func verifyRelease(release *Release, assetName, assetPath string, requireSignature bool) (verified bool, checksums []byte, err error) {
+	checksumsAsset := findAsset(release, checksumsAssetName)
+	if checksumsAsset == nil {
+		return false, nil, fmt.Errorf("release does not publish %s", checksumsAssetName)
+	}
+
+	checksums, err = downloadBytes(checksumsAsset.BrowserDownloadURL)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to download %s: %w", checksumsAssetName, err)
+	}
+
+	want, err := lookupChecksum(checksums, assetName)
+	if err != nil {
+		return false, nil, err
+	}
+
+	got, err := sha256File(assetPath)
+	if err != nil {
+		return false, nil, err
+	}
+	if !strings.EqualFold(want, got) {
+		return false, nil, fmt.Errorf("checksum mismatch for %s: release declares %s, downloaded file hashes to %s", assetName, want, got)
+	}
+
+	verified, sigErr := verifySignature(release, checksums)
+	if sigErr != nil {
+		if requireSignature {
+			return false, nil, sigErr
+		}
+		return false, checksums, nil
+	}
+	if requireSignature && !verified {
+		return false, nil, fmt.Errorf("%s signature did not verify", checksumsAssetName)
+	}
+
+	return verified, checksums, nil
+}
+
+// verifyBinaryChecksumIfPresent checks the extracted binary against
+// checksums.txt too, when the release happens to publish a separate entry
+// for the bare binary name alongside the platform archive. It's a no-op
+// (not an error) when no such entry exists - most release pipelines only
+// hash the archive.
+func verifyBinaryChecksumIfPresent(checksums []byte, binaryPath, binaryName string) error {
+	want, err := lookupChecksum(checksums, binaryName)
+	if err != nil {
+		return nil
+	}
+
+	got, err := sha256File(binaryPath)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(want, got) {
+		return fmt.Errorf("checksum mismatch for extracted %s: release declares %s, got %s", binaryName, want, got)
+	}
+	return nil
+}
+
+// lookupChecksum parses "<sha256hex>  <filename>" lines from checksums.txt
+// (as produced by `sha256sum`) and returns the hex digest for assetName.
+func lookupChecksum(checksums []byte, assetName string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if strings.TrimPrefix(fields[1], "*") == assetName {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%s has no entry for %s", checksumsAssetName, assetName)
+}
+
+// sha256File returns the lowercase hex SHA-256 digest of the file at path.
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// verifySignature verifies checksums against a minisign/signify signature
+// published as the release's checksums.txt.minisig asset, using the
+// Ed25519 public key embedded in updater/keys. Returns false and an error
+// if the release doesn't publish a signature asset at all - verifyRelease
+// decides whether that's acceptable via requireSignature.
+func verifySignature(release *Release, checksums []byte) (bool, error) {
+	sigAsset := findAsset(release, signatureAssetName)
+	if sigAsset == nil {
+		return false, fmt.Errorf("release does not publish %s", signatureAssetName)
+	}
+
+	sigData, err := downloadBytes(sigAsset.BrowserDownloadURL)
+	if err != nil {
+		return false, fmt.Errorf("failed to download %s: %w", signatureAssetName, err)
+	}
+
+	pubKey, err := parseMinisignPublicKey(keys.ReleasePublicKey)
+	if err != nil {
+		return false, fmt.Errorf("embedded release public key is invalid: %w", err)
+	}
+
+	sig, err := parseMinisignSignature(sigData)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %w", signatureAssetName, err)
+	}
+
+	if !ed25519.Verify(pubKey, checksums, sig) {
+		return false, fmt.Errorf("%s signature verification failed", signatureAssetName)
+	}
+
+	return true, nil
+}
+
+// minisignHeaderLen is the size of the fixed header on every minisign
+// public key or signature blob: a 2-byte algorithm tag ("Ed" for Ed25519,
+// non-prehashed) followed by an 8-byte key ID.
+const minisignHeaderLen = 10
+
+// parseMinisignPublicKey decodes the base64 payload line of a minisign
+// public key file into its raw 32-byte Ed25519 key.
+func parseMinisignPublicKey(raw string) (ed25519.PublicKey, error) {
+	blob, err := decodeMinisignBlob(raw)
+	if err != nil {
+		return nil, err
+	}
+	if len(blob) != minisignHeaderLen+ed25519.PublicKeySize {
+		return nil, fmt.Errorf("unexpected public key length %d", len(blob))
+	}
+	return ed25519.PublicKey(blob[minisignHeaderLen:]), nil
+}
+
+// parseMinisignSignature decodes the base64 payload line of a minisign
+// .minisig file into its raw 64-byte Ed25519 signature.
+func parseMinisignSignature(raw []byte) ([]byte, error) {
+	blob, err := decodeMinisignBlob(string(raw))
+	if err != nil {
+		return nil, err
+	}
+	if len(blob) != minisignHeaderLen+ed25519.SignatureSize {
+		return nil, fmt.Errorf("unexpected signature length %d", len(blob))
+	}
+	return blob[minisignHeaderLen:], nil
+}
+
+// decodeMinisignBlob finds the base64-encoded payload line in a minisign
+// file - the first line if it isn't an "untrusted comment:" header,
+// otherwise the line after it - and decodes it. The optional trailing
+// "trusted comment:" + global signature lines used by the full minisign
+// format aren't checked; the embedded Ed25519 signature over the file
+// itself is what's verified here.
+func decodeMinisignBlob(raw string) ([]byte, error) {
+	lines := strings.Split(strings.TrimSpace(raw), "\n")
+	if len(lines) == 0 || lines[0] == "" {
+		return nil, fmt.Errorf("empty minisign file")
+	}
+
+	line := strings.TrimSpace(lines[0])
+	if strings.HasPrefix(line, "untrusted comment:") {
+		if len(lines) < 2 {
+			return nil, fmt.Errorf("missing base64 payload line")
+		}
+		line = strings.TrimSpace(lines[1])
+	}
+
+	return base64.StdEncoding.DecodeString(line)
+}
+
+func findAsset(release *Release, name string) *Asset {
+	for i := range release.Assets {
+		if release.Assets[i].Name == name {
+			return &release.Assets[i]
+		}
+	}
+	return nil
+}
+
+func downloadBytes(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download failed with status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
@@ -4,441 +4,331 @@ import (
 	"bufio"
 	"context"
 	"fmt"
-	"log"
 	"os"
-	"path"
-	"path/filepath"
-	"regexp"
+	"os/exec"
+	"os/signal"
+	"runtime"
 	"strings"
-	"time"
+
+	"uhh/internal/agent"
+	"uhh/internal/config"
+	"uhh/internal/history"
+	"uhh/internal/output"
+	"uhh/internal/provider"
+	"uhh/internal/shell"
+	"uhh/internal/tui"
 
 	"github.com/atotto/clipboard"
-	"github.com/fatih/color"
-	"github.com/mitchellh/go-ps"
+	"github.com/spf13/cobra"
 	"github.com/tmc/langchaingo/llms"
-	"github.com/tmc/langchaingo/llms/openai"
 )
 
-func init() {
-	// Disable colors if requested via environment variable
-	if os.Getenv("UHH_NO_COLOR") != "" || os.Getenv("NO_COLOR") != "" {
-		color.NoColor = true
+// version is set via ldflags at build time.
+var version = "dev"
+
+var (
+	// Persistent flags, available to run/revise/agent. These replace the old
+	// "!shell=" prefix hack, the bare "--shell" pre-cobra parsing, and the
+	// UHH_SHELL / UHH_MODEL / UHH_APPEND_SMALL_CONTEXT env vars as the primary
+	// way to configure a single invocation (the env vars still work as
+	// config-level fallbacks via config.Load, but flags now take priority).
+	shellFlag         string
+	providerFlag      string
+	modelFlag         string
+	appendContextFlag bool
+	contextTokensFlag int
+	interactiveFlag   bool
+	candidatesFlag    int
+
+	rootCmd = &cobra.Command{
+		Use:   "uhh [prompt]",
+		Short: "AI-powered terminal command assistant",
+		Long: `UHH is an AI-powered CLI tool that helps you generate shell commands
+from natural language descriptions. Running it with a bare prompt is
+shorthand for "uhh run <prompt>".`,
+		Args: cobra.ArbitraryArgs,
+		RunE: runRun,
+	}
+
+	runCmd = &cobra.Command{
+		Use:   "run [prompt]",
+		Short: "Generate a shell command from a prompt (default command)",
+		Args:  cobra.ArbitraryArgs,
+		RunE:  runRun,
+	}
+
+	reviseCmd = &cobra.Command{
+		Use:   "revise <addendum>",
+		Short: "Revise the last prompt with additional instructions",
+		Long:  `Revise replaces the old "actually ..." magic prefix: it reuses the last logged prompt and shell, appending addendum to it.`,
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runRevise,
 	}
+)
 
-	// Set up OpenAI API key
-	token := os.Getenv("OPENAI_API_KEY")
-	if token != "" {
-		return
+func init() {
+	output.InitColors()
+
+	rootCmd.PersistentFlags().StringVarP(&shellFlag, "shell", "s", "", "Override shell detection (powershell, cmd, bash, zsh, fish)")
+	rootCmd.PersistentFlags().StringVarP(&providerFlag, "provider", "p", "", "LLM provider to use (openai, gemini, deepseek, kimi, glm)")
+	rootCmd.PersistentFlags().StringVarP(&modelFlag, "model", "m", "", "Model to use")
+	rootCmd.PersistentFlags().BoolVar(&appendContextFlag, "append-context", false, "Append small referenced-file contents to the prompt")
+	rootCmd.PersistentFlags().IntVar(&contextTokensFlag, "context-tokens", 0, "Max tokens of file context to append (implies --append-context)")
+	rootCmd.PersistentFlags().BoolVarP(&interactiveFlag, "interactive", "i", false, "Review candidates and confirm before running/copying (also UHH_INTERACTIVE=1)")
+	rootCmd.PersistentFlags().IntVar(&candidatesFlag, "candidates", 3, "Number of candidates to generate in interactive mode")
+
+	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(reviseCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(agentCmd)
+	rootCmd.AddCommand(providerCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "version",
+		Short: "Print version information",
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Printf("uhh version %s\n", version)
+		},
+	})
+}
+
+func main() {
+	for _, err := range provider.Load() {
+		output.PrintWarn(fmt.Sprintf("plugin: %v", err))
 	}
-	home, err := os.UserHomeDir()
-	if err != nil {
-		log.Println("Warning: Failed to get user home directory:", err)
-		return
+	defer provider.Shutdown()
+
+	if err := rootCmd.Execute(); err != nil {
+		output.PrintError(err.Error())
+		os.Exit(1)
 	}
-	file, err := os.ReadFile(path.Join(home, ".openai.token.txt"))
+}
+
+// loadEffectiveConfig loads the config and overlays the shell/append-context
+// flags on top of it, so downstream code only has to look at cfg.
+func loadEffectiveConfig() (*config.Config, error) {
+	cfg, err := config.Load()
 	if err != nil {
-		log.Println("Warning: Failed to read token file:", err)
-		return
+		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
-	fileToken := strings.TrimSpace(string(file))
-	if fileToken == "" {
-		log.Printf("Warning: No OpenAI API key found in environment variable or file.")
-	}
-	err = os.Setenv("OPENAI_API_KEY", fileToken)
-	if err != nil {
-		log.Println("Warning: Failed to set OpenAI API key from file:", err)
+
+	if cfg.UI.NoColor {
+		output.DisableColors()
 	}
-}
 
-func DetectParentShell() string {
-	pid := os.Getpid()
-	proc, err := ps.FindProcess(pid)
-	if err != nil || proc == nil {
-		return "unknown"
+	if appendContextFlag || contextTokensFlag > 0 {
+		cfg.Shell.AppendFileContext = true
 	}
-	for i := 0; i < 10; i++ {
-		proc, err = ps.FindProcess(proc.PPid())
-		if err != nil || proc == nil {
-			break
-		}
-		name := strings.ToLower(proc.Executable())
-		switch {
-		case strings.Contains(name, "powershell") || strings.Contains(name, "pwsh"):
-			return "powershell"
-		case name == "cmd.exe":
-			return "cmd"
-		case strings.Contains(name, "bash"):
-			return "bash"
-		case strings.Contains(name, "zsh"):
-			return "zsh"
-		case strings.Contains(name, "fish"):
-			return "fish"
-		}
+	if contextTokensFlag > 0 {
+		cfg.Shell.MaxContextTokens = contextTokensFlag
 	}
-	return "unknown"
+
+	return cfg, nil
 }
 
-func Prompt(query string, shell string) string {
-	p := `
-<instruction>
-You are a autocorrect system for a terminal, your environment is %shell%. When presented an input you fix and/or change it into a compatible %shell% command that can be executed.
-</instruction>
-<user_input>
-%query%
-</user_input>
-<output>
-Only output a command that can be immediately executed.
-DO NOT wrap in code blocks or anything else.
-</output>
-`
-	p = strings.ReplaceAll(p, "%shell%", shell)
-	p = strings.ReplaceAll(p, "%query%", query)
-
-	// Append small file context if enabled
-	p = AppendSmallFileContext(p, query)
-
-	return p
+// resolveShell determines which shell name to build the prompt for.
+func resolveShell(cfg *config.Config) string {
+	return shell.DetermineShell(shellFlag, cfg.Shell.Override)
 }
 
-func GetHistoryPath() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "./.uhh.history.txt"
+// resolveProvider initializes the provider named by --provider, or the
+// config's active provider if the flag wasn't given. It returns the settings
+// used alongside the provider so callers don't have to re-resolve the name.
+func resolveProvider(cfg *config.Config) (provider.Provider, config.ProviderSettings, error) {
+	name := providerFlag
+	if name == "" {
+		name = cfg.GetActiveProvider()
 	}
-	return path.Join(home, ".uhh.history.txt")
-}
 
-func LogHistory(entry string) {
-	histPath := GetHistoryPath()
-	f, err := os.OpenFile(histPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
-	if err != nil {
-		// Use regular log for internal warnings to avoid disrupting output flow
-		log.Printf("Warning: Failed to write history: %v", err)
-		return
+	settings, ok := cfg.GetProviderSettings(name)
+	if !ok {
+		return nil, config.ProviderSettings{}, fmt.Errorf("unknown provider: %s", name)
 	}
-	defer f.Close()
-	fmt.Fprintln(f, entry)
-}
 
-func LoadLastPrompt() (string, string) {
-	histPath := GetHistoryPath()
-	file, err := os.Open(histPath)
-	if err != nil {
-		return "", ""
-	}
-	defer file.Close()
-	var lastPrompt, lastShell string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.HasPrefix(line, "Prompt: ") {
-			lastPrompt = strings.TrimPrefix(line, "Prompt: ")
-		}
-		if strings.HasPrefix(line, "Shell: ") {
-			lastShell = strings.TrimPrefix(line, "Shell: ")
+	// A provider declared directly in config via Command (rather than
+	// discovered under provider.PluginsDir()) is spawned and registered here,
+	// on first use.
+	if settings.Command != "" {
+		if err := provider.RegisterCommand(name, settings.Command, settings.Args, settings.Env, settings.Socket); err != nil {
+			return nil, config.ProviderSettings{}, fmt.Errorf("failed to start provider plugin %s: %w", name, err)
 		}
 	}
-	return lastPrompt, lastShell
-}
 
-func GetUserPrompt() string {
-	prompts := strings.Join(os.Args[1:], " ")
-	if prompts == "" {
-		fmt.Println("Please enter your prompt:")
-		scanner := bufio.NewScanner(os.Stdin)
-		if scanner.Scan() {
-			prompts = scanner.Text()
-		}
+	model := modelFlag
+	if model == "" {
+		model = settings.Model
 	}
-	if prompts == "" {
-		fmt.Println("No prompt provided. Exiting.")
-		os.Exit(1)
+	if model == "" {
+		model = config.DefaultModels[name]
 	}
-	return prompts
+
+	p, err := provider.GetAndInitialize(name, provider.Config{
+		APIKey:      settings.APIKey,
+		Model:       model,
+		BaseURL:     settings.BaseURL,
+		Temperature: settings.Temperature,
+	})
+	return p, settings, err
 }
 
-// ParseShellOverride extracts shell override from arguments and returns cleaned prompt and shell
-func ParseShellOverride(args []string) ([]string, string) {
-	var cleanedArgs []string
-	var shellOverride string
-
-	for i, arg := range args {
-		if strings.HasPrefix(arg, "!shell=") {
-			// Handle !shell=cmd format
-			shellOverride = strings.TrimPrefix(arg, "!shell=")
-		} else if arg == "--shell" && i+1 < len(args) {
-			// Handle --shell cmd format
-			shellOverride = args[i+1]
-			// Skip the next argument as it's the shell value
-			i++
-		} else {
-			cleanedArgs = append(cleanedArgs, arg)
-		}
+// pricingTable translates cfg's plain config.ModelPricing map into a
+// provider.PricingTable, keeping the provider package free of any
+// dependency on config (mirroring how provider.Config and
+// config.ProviderSettings stay distinct types bridged here).
+func pricingTable(cfg *config.Config) provider.PricingTable {
+	table := make(provider.PricingTable, len(cfg.Pricing))
+	for model, p := range cfg.Pricing {
+		table[model] = provider.ModelPricing{InputPer1K: p.InputPer1K, OutputPer1K: p.OutputPer1K}
 	}
-
-	return cleanedArgs, shellOverride
+	return table
 }
 
-// GetUserPromptAndShell parses command line arguments for both prompt and shell override
-func GetUserPromptAndShell() (string, string) {
-	cleanedArgs, shellOverride := ParseShellOverride(os.Args[1:])
+// formatUsage renders a result's token/cost accounting for the
+// "Used N tools..." summary line, omitting the cost clause when no pricing
+// was configured for the model that produced it.
+func formatUsage(u agent.Usage) string {
+	if u.TotalTokens == 0 {
+		return ""
+	}
+	if u.CostUSD > 0 {
+		return fmt.Sprintf(", %d tokens ($%.4f)", u.TotalTokens, u.CostUSD)
+	}
+	return fmt.Sprintf(", %d tokens", u.TotalTokens)
+}
 
-	prompts := strings.Join(cleanedArgs, " ")
-	if prompts == "" {
-		PrintPrompt("What do you want? ")
+// readPromptArgs joins args into a prompt, falling back to an interactive
+// stdin read when the command was invoked bare.
+func readPromptArgs(args []string) string {
+	userPrompt := strings.Join(args, " ")
+	if userPrompt == "" {
+		output.PrintPrompt("What do you want? ")
 		scanner := bufio.NewScanner(os.Stdin)
 		if scanner.Scan() {
-			prompts = scanner.Text()
+			userPrompt = scanner.Text()
 		}
 	}
-	if prompts == "" {
-		PrintWarn("No prompt provided. Exiting.")
-		os.Exit(1)
-	}
-
-	return prompts, shellOverride
+	return userPrompt
 }
 
-// DetermineShell determines the shell to use based on overrides and detection
-func DetermineShell(argShellOverride string) string {
-	// Priority: 1) Command line argument, 2) Environment variable, 3) Auto-detection
+func runRun(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-	// Check command line argument first
-	if argShellOverride != "" {
-		return normalizeShellName(argShellOverride)
+	cfg, err := loadEffectiveConfig()
+	if err != nil {
+		return err
 	}
 
-	// Check environment variable
-	if envShell := os.Getenv("UHH_SHELL"); envShell != "" {
-		return normalizeShellName(envShell)
+	userPrompt := readPromptArgs(args)
+	if userPrompt == "" {
+		output.PrintWarn("No prompt provided. Exiting.")
+		os.Exit(1)
 	}
 
-	// Fall back to auto-detection
-	return DetectParentShell()
+	return generateAndEmit(ctx, cfg, userPrompt)
 }
 
-// normalizeShellName normalizes shell names to standard values
-func normalizeShellName(shell string) string {
-	shell = strings.ToLower(strings.TrimSpace(shell))
-
-	switch {
-	case shell == "powershell" || shell == "pwsh" || shell == "ps":
-		return "powershell"
-	case shell == "cmd" || shell == "command":
-		return "cmd"
-	case shell == "bash":
-		return "bash"
-	case shell == "zsh":
-		return "zsh"
-	case shell == "fish":
-		return "fish"
-	default:
-		return shell // Return as-is if not recognized
-	}
-}
+func runRevise(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-// ExtractFileReferences finds potential file paths in the user prompt
-func ExtractFileReferences(text string) []string {
-	var files []string
-
-	// Common file patterns
-	patterns := []string{
-		// Files with extensions
-		`\b[\w\-\.\/\\]+\.[a-zA-Z0-9]+\b`,
-		// Quoted file paths
-		`["']([^"']+\.[a-zA-Z0-9]+)["']`,
-		// Common config files without need for extensions
-		`\b(package\.json|go\.mod|go\.sum|Dockerfile|Makefile|README\.md|\.gitignore)\b`,
-	}
-
-	for _, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
-		matches := re.FindAllString(text, -1)
-		for _, match := range matches {
-			// Clean up quotes if present
-			match = strings.Trim(match, `"'`)
-			files = append(files, match)
-		}
+	cfg, err := loadEffectiveConfig()
+	if err != nil {
+		return err
 	}
 
-	return files
-}
-
-// IsSmallFile checks if a file exists and is small enough to include
-func IsSmallFile(filePath string, maxTokens int) (bool, error) {
-	// Convert relative paths to absolute
-	if !filepath.IsAbs(filePath) {
-		abs, err := filepath.Abs(filePath)
-		if err == nil {
-			filePath = abs
-		}
+	lastPrompt, lastShell := history.LoadLastEntry()
+	if lastPrompt == "" {
+		output.PrintWarn("No history found for revision.")
+		return nil
 	}
-
-	info, err := os.Stat(filePath)
-	if err != nil {
-		return false, err
+	if shellFlag == "" && cfg.Shell.Override == "" {
+		shellFlag = lastShell
 	}
 
-	// Rough approximation: 1 token ≈ 4 characters
-	maxBytes := int64(maxTokens * 4)
-	return info.Size() <= maxBytes, nil
+	addendum := strings.Join(args, " ")
+	userPrompt := lastPrompt + ". " + addendum
+	output.PrintInfo("Revising previous prompt with new info...")
+
+	return generateAndEmit(ctx, cfg, userPrompt)
 }
 
-// AppendSmallFileContext adds content of small referenced files to the prompt
-func AppendSmallFileContext(prompt, userPrompt string) string {
-	appendContext := os.Getenv("UHH_APPEND_SMALL_CONTEXT")
-	if appendContext == "" || strings.ToLower(appendContext) == "false" || appendContext == "0" {
-		return prompt
+// generateAndEmit builds the prompt, calls the provider, prints/copies/logs
+// the result. Shared by run and revise so their behavior stays identical.
+func generateAndEmit(ctx context.Context, cfg *config.Config, userPrompt string) error {
+	p, settings, err := resolveProvider(cfg)
+	if err != nil {
+		return err
 	}
 
-	maxTokens := 1000 // Default to 1000 tokens
-	if appendContext != "true" && appendContext != "1" {
-		// Try to parse as number
-		if tokens := parseTokenLimit(appendContext); tokens > 0 {
-			maxTokens = tokens
-		}
-	}
+	shellName := resolveShell(cfg)
+	prompt := shell.BuildPrompt(userPrompt, shellName, cfg.Shell.AppendFileContext, cfg.Shell.MaxContextTokens)
 
-	files := ExtractFileReferences(userPrompt)
-	var contextFiles []string
-	var contextFileNames []string
+	if tui.InteractiveEnabled(interactiveFlag) {
+		return generateInteractive(ctx, p, settings, prompt, shellName, userPrompt)
+	}
 
-	for _, file := range files {
-		if small, err := IsSmallFile(file, maxTokens); err == nil && small {
-			content, err := os.ReadFile(file)
-			if err == nil {
-				contextFiles = append(contextFiles, fmt.Sprintf("File: %s\n%s", file, string(content)))
-				contextFileNames = append(contextFileNames, file)
-			}
-		}
+	completion, err := p.Call(ctx, prompt, llms.WithTemperature(settings.Temperature))
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
 	}
 
-	if len(contextFiles) > 0 {
-		contextSection := "<file_contexts>"
-		for i, fileContent := range contextFiles {
-			contextSection += "\n<file name='" + filepath.Base(contextFileNames[i]) + "'>\n"
-			contextSection += fileContent + "\n"
-			contextSection += "</file>\n"
-		}
-		contextSection += "</file_contexts>\n"
-		return strings.Replace(prompt, "<user_input>", contextSection+"\n<user_input>", 1)
+	output.PrintCommand(completion)
+	if err := clipboard.WriteAll(completion); err == nil {
+		output.PrintSuccess("Copied to clipboard!")
 	}
 
-	return prompt
+	history.Log(shellName, userPrompt, completion)
+	return nil
 }
 
-// parseTokenLimit attempts to parse a string as a token limit
-func parseTokenLimit(s string) int {
-	// Simple parsing - just look for numbers
-	re := regexp.MustCompile(`\d+`)
-	if match := re.FindString(s); match != "" {
-		var num int
-		if n, err := fmt.Sscanf(match, "%d", &num); n == 1 && err == nil {
-			return num
-		}
+// generateInteractive requests a batch of candidates and lets the user
+// review, edit, or regenerate them before anything runs or touches the
+// clipboard (see tui.SelectCandidateAction).
+func generateInteractive(ctx context.Context, p provider.Provider, settings config.ProviderSettings, prompt, shellName, userPrompt string) error {
+	regenerate := func() ([]string, error) {
+		return tui.GenerateCandidates(ctx, p, prompt, settings.Temperature, candidatesFlag)
 	}
-	return 0
-}
 
-func GetModel() string {
-	model := "gpt-4o"
-	if modelEnv := os.Getenv("UHH_MODEL"); modelEnv != "" {
-		model = modelEnv
+	candidates, err := regenerate()
+	if err != nil {
+		return err
 	}
-	return model
-}
-
-// Color utility functions
-var (
-	// Command output in bright green
-	cmdColor = color.New(color.FgHiGreen, color.Bold)
-	// Success messages in green
-	successColor = color.New(color.FgGreen)
-	// Info messages in cyan
-	infoColor = color.New(color.FgCyan)
-	// Warning messages in yellow
-	warnColor = color.New(color.FgYellow)
-	// Error messages in red
-	errorColor = color.New(color.FgRed, color.Bold)
-	// Prompt text in blue
-	promptColor = color.New(color.FgBlue)
-)
-
-// PrintCommand prints the generated command in bright green
-func PrintCommand(cmd string) {
-	cmdColor.Println(cmd)
-}
-
-// PrintSuccess prints success messages in green
-func PrintSuccess(msg string) {
-	successColor.Println(msg)
-}
-
-// PrintInfo prints informational messages in cyan
-func PrintInfo(msg string) {
-	infoColor.Println(msg)
-}
-
-// PrintWarn prints warning messages in yellow
-func PrintWarn(msg string) {
-	warnColor.Println(msg)
-}
-
-// PrintError prints error messages in red
-func PrintError(msg string) {
-	errorColor.Println(msg)
-}
 
-// PrintPrompt prints prompt text in blue
-func PrintPrompt(msg string) {
-	promptColor.Print(msg)
-}
-
-func main() {
-	llm, err := openai.New(
-		openai.WithModel(GetModel()),
-	)
+	action, command, err := tui.SelectCandidateAction(candidates, regenerate)
 	if err != nil {
-		log.Fatal(err)
-	}
-	ctx := context.Background()
-	userPrompt, shellOverride := GetUserPromptAndShell()
-	shell := DetermineShell(shellOverride)
-
-	// Handle "actually" rewrite
-	if strings.HasPrefix(strings.ToLower(userPrompt), "actually") {
-		addendum := strings.TrimSpace(userPrompt[len("actually"):])
-		lastPrompt, lastShell := LoadLastPrompt()
-		if lastPrompt != "" {
-			// Only use last shell if no override is specified
-			if shellOverride == "" && os.Getenv("UHH_SHELL") == "" {
-				shell = lastShell
-			}
-			userPrompt = lastPrompt + ". " + addendum
-			PrintInfo("→ Revising previous prompt with new info...")
-		} else {
-			PrintWarn("No history found for revision.")
-		}
+		return err
 	}
 
-	// fmt.Println("Using shell:", shell)
-	prompt := Prompt(userPrompt, shell)
-	completion, err := llm.Call(ctx, prompt, llms.WithTemperature(1))
-	if err != nil {
-		PrintError(fmt.Sprintf("Error: %v", err))
-		os.Exit(1)
+	switch action {
+	case tui.ActionRun:
+		if err := runShellCommand(ctx, command); err != nil {
+			return err
+		}
+	case tui.ActionCopy:
+		if err := clipboard.WriteAll(command); err == nil {
+			output.PrintSuccess("Copied to clipboard!")
+		}
+	case tui.ActionCancel:
+		output.PrintInfo("Cancelled.")
+		return nil
 	}
-	PrintCommand(completion)
-	PrintSuccess("✓ Copied to clipboard!")
-	_ = clipboard.WriteAll(completion)
-
-	// Log history
-	histEntry := fmt.Sprintf(
-		"Time: %s\nShell: %s\nPrompt: %s\nOutput: %s\n---",
-		time.Now().Format(time.RFC3339),
-		shell,
-		userPrompt,
-		completion,
-	)
-	LogHistory(histEntry)
+
+	history.Log(shellName, userPrompt, command)
+	return nil
+}
+
+// runShellCommand executes command via the platform shell, the same way
+// tools.BashTool does, streaming output straight to the terminal.
+func runShellCommand(ctx context.Context, command string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
 }
@@ -0,0 +1,42 @@
+// Package plugin is a small helper for writing an uhh provider plugin in Go:
+// a subprocess that uhh spawns (see provider.Load and provider.RegisterCommand
+// in internal/provider) and that serves grpcpb.InferenceServiceServer over
+// the Unix socket named by the UHH_PLUGIN_SOCKET environment variable.
+//
+// Plugins written in other languages don't need this package, but do need
+// to match more than the message shapes in internal/provider/grpcpb/
+// inference.proto: uhh's client forces grpcpb.JSONCodec (see its doc
+// comment), so every RPC is a JSON object on the wire, not the protobuf
+// encoding a real protoc-gen-* for their language would produce.
+package plugin
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"uhh/internal/provider/grpcpb"
+
+	"google.golang.org/grpc"
+)
+
+// Serve listens on the socket path named by UHH_PLUGIN_SOCKET (set by uhh
+// when it spawns the plugin) and blocks serving impl until the listener
+// errors or the process is killed.
+func Serve(impl grpcpb.InferenceServiceServer) error {
+	socket := os.Getenv("UHH_PLUGIN_SOCKET")
+	if socket == "" {
+		return fmt.Errorf("plugin: UHH_PLUGIN_SOCKET is not set; this binary must be spawned by uhh")
+	}
+	os.Remove(socket)
+
+	lis, err := net.Listen("unix", socket)
+	if err != nil {
+		return fmt.Errorf("plugin: failed to listen on %s: %w", socket, err)
+	}
+
+	srv := grpc.NewServer(grpc.ForceServerCodec(grpcpb.JSONCodec{}))
+	grpcpb.RegisterInferenceServiceServer(srv, impl)
+
+	return srv.Serve(lis)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+
+	"uhh/internal/config"
+	"uhh/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	providerCmd = &cobra.Command{
+		Use:   "provider",
+		Short: "List or switch the default LLM provider",
+	}
+
+	providerListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List configured providers",
+		Run:   runProviderList,
+	}
+
+	providerSetCmd = &cobra.Command{
+		Use:   "set <name>",
+		Short: "Set the default provider",
+		Args:  cobra.ExactArgs(1),
+		Run:   runProviderSet,
+	}
+)
+
+func init() {
+	providerCmd.AddCommand(providerListCmd)
+	providerCmd.AddCommand(providerSetCmd)
+}
+
+func runProviderList(cmd *cobra.Command, args []string) {
+	cfg, err := config.Load()
+	if err != nil {
+		output.PrintError(fmt.Sprintf("Failed to load config: %v", err))
+		return
+	}
+
+	for _, name := range config.AllProviders() {
+		settings, ok := cfg.Providers[name]
+		marker := "  "
+		if name == cfg.DefaultProvider {
+			marker = "* "
+		}
+		status := "disabled"
+		if ok && settings.Enabled {
+			status = "enabled"
+		}
+		fmt.Printf("%s%s (%s) - %s\n", marker, name, config.ProviderDisplayNames[name], status)
+	}
+}
+
+func runProviderSet(cmd *cobra.Command, args []string) {
+	name := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		output.PrintError(fmt.Sprintf("Failed to load config: %v", err))
+		return
+	}
+
+	if _, ok := cfg.Providers[name]; !ok {
+		output.PrintError(fmt.Sprintf("Unknown provider: %s", name))
+		return
+	}
+
+	cfg.DefaultProvider = name
+	if err := cfg.Save(); err != nil {
+		output.PrintError(fmt.Sprintf("Failed to save config: %v", err))
+		return
+	}
+
+	output.PrintSuccess(fmt.Sprintf("Default provider set to: %s", name))
+}